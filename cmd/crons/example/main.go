@@ -3,33 +3,129 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/SmirnovND/gobase/internal/config"
+	cronconfig "github.com/SmirnovND/gobase/internal/config/cron"
 	"github.com/SmirnovND/gobase/internal/container"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
 	"go.uber.org/zap"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
 	if err := Run(); err != nil {
-		fmt.Fprintf(nil, "cron failed: %v", err)
-		panic(err)
+		fmt.Fprintf(os.Stderr, "cron failed: %v\n", err)
+		os.Exit(1)
 	}
 }
 
 func Run() error {
-	diContainer := container.NewContainer()
+	// --print-config выводит итоговую конфигурацию (секреты замаскированы) и завершает работу
+	if config.PrintConfigRequested(os.Args[1:]) {
+		cf, err := cronconfig.Load(os.Args[1:], os.Environ())
+		if err != nil {
+			return err
+		}
+		return cf.Print(os.Stdout)
+	}
+
+	diContainer, err := container.NewContainer(container.CronConfig)
+	if err != nil {
+		return err
+	}
+	defer diContainer.Close()
 
 	var logger *zap.Logger
-	if err := diContainer.Invoke(func(l *zap.Logger) {
+	var cf interfaces.ConfigCron
+	if err := diContainer.Invoke(func(l *zap.Logger, c interfaces.ConfigCron) {
 		logger = l
+		cf = c
 	}); err != nil {
 		return err
 	}
+	// Остановка по этапам с дедлайнами блока shutdown; Close выше после неё ничего не делает
+	defer func() {
+		if err := diContainer.Stop(cf); err != nil {
+			logger.Error("Container shutdown failed", zap.Error(err))
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	job, err := cronJob(diContainer, cf.GetCronJob(), logger)
+	if err != nil {
+		return err
+	}
+
+	if err := diContainer.Start(ctx); err != nil {
+		return err
+	}
+
+	// Без расписания задача выполняется один раз (crontab, Kubernetes CronJob)
+	if cf.GetCronSchedule() == 0 {
+		return runOnce(ctx, cf, job, logger)
+	}
+
+	logger.Info("Starting cron job", zap.String("job", job.Name), zap.Duration("schedule", cf.GetCronSchedule()))
+	ticker := time.NewTicker(cf.GetCronSchedule())
+	defer ticker.Stop()
 
-	logger.Info("Starting example cron job")
+	for {
+		if err := runOnce(ctx, cf, job, logger); err != nil && ctx.Err() == nil {
+			// Ошибка одного запуска не останавливает расписание
+			logger.Error("Cron run failed, waiting for next schedule", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Cron stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// cronJob возвращает задачу модуля с именем cron.job или ExampleJob, если имя не задано
+func cronJob(diContainer *container.Container, name string, logger *zap.Logger) (module.Job, error) {
+	if name == "" {
+		return module.Job{
+			Name: "example",
+			Run: func(ctx context.Context) error {
+				return ExampleJob(ctx, logger)
+			},
+		}, nil
+	}
+
+	var jobs []module.Job
+	if err := diContainer.Invoke(func(in module.JobsIn) {
+		jobs = in.Jobs
+	}); err != nil {
+		return module.Job{}, err
+	}
+
+	for _, job := range jobs {
+		if job.Name == name {
+			return job, nil
+		}
+	}
+	return module.Job{}, fmt.Errorf("cron.job: unknown job %s (module disabled or not registered)", name)
+}
+
+// runOnce выполняет один запуск задачи с учётом cron.timeout
+func runOnce(ctx context.Context, cf interfaces.ConfigCron, job module.Job, logger *zap.Logger) error {
+	if cf.GetCronTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cf.GetCronTimeout())
+		defer cancel()
+	}
 
-	ctx := context.Background()
+	logger.Info("Running cron job", zap.String("job", job.Name))
 
-	// Здесь ваша логика крон скрипта
-	if err := ExampleJob(ctx, logger); err != nil {
+	if err := job.Run(ctx); err != nil {
 		logger.Error("Cron job failed", zap.Error(err))
 		return err
 	}
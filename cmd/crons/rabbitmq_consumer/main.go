@@ -3,9 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/SmirnovND/gobase/internal/config"
+	consumerconfig "github.com/SmirnovND/gobase/internal/config/consumer"
 	"github.com/SmirnovND/gobase/internal/container"
-	"github.com/SmirnovND/toolbox/pkg/rabbitmq"
+	"github.com/SmirnovND/gobase/internal/infra"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/streadway/amqp"
 	"go.uber.org/zap"
 	"os"
 	"os/signal"
@@ -21,36 +27,62 @@ func main() {
 }
 
 func Run() error {
-	diContainer := container.NewContainer()
+	// --print-config выводит итоговую конфигурацию (секреты замаскированы) и завершает работу
+	if config.PrintConfigRequested(os.Args[1:]) {
+		cf, err := consumerconfig.Load(os.Args[1:], os.Environ())
+		if err != nil {
+			return err
+		}
+		return cf.Print(os.Stdout)
+	}
+
+	diContainer, err := container.NewContainer(container.ConsumerConfig)
+	if err != nil {
+		return err
+	}
 	defer diContainer.Close()
 
 	var logger *zap.Logger
-	if err := diContainer.Invoke(func(l *zap.Logger) {
+	var cf interfaces.ConfigConsumer
+	var lc interfaces.Lifecycle
+	if err := diContainer.Invoke(func(l *zap.Logger, c interfaces.ConfigConsumer, lifecycle interfaces.Lifecycle) {
 		logger = l
+		cf = c
+		lc = lifecycle
 	}); err != nil {
 		return err
 	}
 
-	var conn *rabbitmq.RabbitMQConnection
-	if err := diContainer.Invoke(func(c *rabbitmq.RabbitMQConnection) {
+	var conn *infra.RabbitMQConn
+	if err := diContainer.Invoke(func(c *infra.RabbitMQConn) {
 		conn = c
 	}); err != nil {
 		return err
 	}
 
-	if conn == nil {
-		return fmt.Errorf("failed to create RabbitMQ connection")
+	// Обработчик очереди объявляется в модуле фичи (module.Consumer),
+	// без него сообщения обрабатывает handleMessage
+	handle, err := queueHandler(diContainer, cf.GetConsumerQueue(), logger)
+	if err != nil {
+		return err
 	}
 
-	// Создаем consumer для конкретной очереди
-	// Здесь используется очередь "tasks_queue", замените на вашу
-	consumer := rabbitmq.NewRabbitMQConsumer(conn.Conn, "tasks_queue")
-	defer func() {
-		consumer.Close()
-		logger.Info("Consumer closed")
-	}()
-
-	logger.Info("RabbitMQ consumer started")
+	// Очередь и prefetch берутся из секции consumer конфигурации. Consumer
+	// подписывается заново на актуальном соединении после переподключения.
+	consumer := infra.NewRabbitMQConsumer(conn, cf.GetConsumerQueue(), cf.GetConsumerPrefetch(), logger)
+	// Канал закрывается раньше подключения: хуки останавливаются в обратном порядке
+	lc.Append(interfaces.Hook{
+		Name: "rabbitmq.consumer." + cf.GetConsumerQueue(),
+		OnStop: func(ctx context.Context) error {
+			defer logger.Info("Consumer closed")
+			return consumer.Close()
+		},
+	})
+
+	logger.Info("RabbitMQ consumer started",
+		zap.String("queue", cf.GetConsumerQueue()),
+		zap.Int("prefetch", cf.GetConsumerPrefetch()),
+	)
 
 	// Создаем контекст для отмены
 	ctx, cancel := context.WithCancel(context.Background())
@@ -66,34 +98,71 @@ func Run() error {
 		cancel()
 	}()
 
-	// Запускаем потребление сообщений
-	return consumeMessages(ctx, consumer, logger)
-}
+	if err := diContainer.Start(ctx); err != nil {
+		return err
+	}
 
-// consumeMessages осуществляет потребление и обработку сообщений
-func consumeMessages(ctx context.Context, consumer *rabbitmq.RabbitMQConsumer, logger *zap.Logger) error {
-	messages, err := consumer.Consume()
+	messages, err := consumer.Consume(ctx)
 	if err != nil {
 		logger.Error("Failed to start consuming messages", zap.Error(err))
 		return err
 	}
 
+	// Запускаем потребление сообщений
+	consumeErr := consumeMessages(ctx, messages, handle, logger)
+
+	// Сначала компоненты, затем подключения - каждый этап со своим дедлайном
+	return errors.Join(consumeErr, diContainer.Stop(cf))
+}
+
+// queueHandler возвращает обработчик очереди из включённых модулей
+// или handleMessage, если ни один модуль не обрабатывает эту очередь
+func queueHandler(diContainer *container.Container, queue string, logger *zap.Logger) (func(ctx context.Context, body []byte) error, error) {
+	var consumers []module.Consumer
+	if err := diContainer.Invoke(func(in module.ConsumersIn) {
+		consumers = in.Consumers
+	}); err != nil {
+		return nil, err
+	}
+
+	var handle func(ctx context.Context, body []byte) error
+	for _, c := range consumers {
+		if c.Queue != queue {
+			continue
+		}
+		if handle != nil {
+			return nil, fmt.Errorf("queue %s is handled by several modules", queue)
+		}
+		handle = c.Handle
+	}
+
+	if handle == nil {
+		logger.Warn("No module consumer for queue, using default handler", zap.String("queue", queue))
+		return func(ctx context.Context, body []byte) error {
+			return handleMessage(body, logger)
+		}, nil
+	}
+	return handle, nil
+}
+
+// consumeMessages осуществляет потребление и обработку сообщений
+func consumeMessages(ctx context.Context, messages <-chan amqp.Delivery, handle func(ctx context.Context, body []byte) error, logger *zap.Logger) error {
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("Consumer context cancelled")
 			return nil
 
-		case msg := <-messages:
-			if msg.DeliveryTag == 0 {
-				logger.Info("Consumer closed")
+		case msg, ok := <-messages:
+			if !ok {
+				// Поток закрывается только после отмены контекста
 				return nil
 			}
 
 			startTime := time.Now()
 
 			// Обработка сообщения
-			if err := handleMessage(msg.Body, logger); err != nil {
+			if err := handle(ctx, msg.Body); err != nil {
 				logger.Error("Failed to process message", zap.Error(err))
 				// Отклоняем сообщение и возвращаем в очередь для повторной обработки
 				msg.Nack(false, true)
@@ -109,8 +178,8 @@ func consumeMessages(ctx context.Context, consumer *rabbitmq.RabbitMQConsumer, l
 	}
 }
 
-// handleMessage обрабатывает полученное сообщение
-// Рекомендуемый паттерн использования:
+// handleMessage обрабатывает сообщение очереди, для которой нет module.Consumer.
+// Рекомендуемый паттерн - объявить module.Consumer в модуле фичи (internal/modules):
 // 1. Распарсьте сообщение в нужную вам структуру (task, event, и т.д.)
 // 2. Вызовите соответствующий UseCase или Service через DI контейнер
 // 3. НЕ размещайте бизнес-логику прямо здесь - это просто транспортный слой
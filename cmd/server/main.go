@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/SmirnovND/gobase/internal/config"
+	serverconfig "github.com/SmirnovND/gobase/internal/config/server"
 	"github.com/SmirnovND/gobase/internal/container"
+	"github.com/SmirnovND/gobase/internal/infra"
 	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/lifecycle"
+	"github.com/SmirnovND/gobase/internal/middleware"
+	"github.com/SmirnovND/gobase/internal/module"
 	"github.com/SmirnovND/gobase/internal/router"
-	"github.com/SmirnovND/toolbox/pkg/logger"
-	"github.com/SmirnovND/toolbox/pkg/middleware"
 	"github.com/SmirnovND/toolbox/pkg/migrations"
-	"github.com/SmirnovND/toolbox/pkg/rabbitmq"
 	"github.com/jmoiron/sqlx"
 	"log"
 	"net/http"
@@ -36,23 +40,62 @@ import (
 // @BasePath  /
 
 // @schemes http https
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT: "Bearer <token>"
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        Authorization
+// @description                 API ключ: "ApiKey <key>"
 func main() {
 	if err := Run(); err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "server failed: %v\n", err)
+		os.Exit(1)
 	}
 }
 
 func Run() error {
-	diContainer := container.NewContainer()
+	// --print-config выводит итоговую конфигурацию (секреты замаскированы) и завершает работу
+	if config.PrintConfigRequested(os.Args[1:]) {
+		cf, err := serverconfig.Load(os.Args[1:], os.Environ())
+		if err != nil {
+			return err
+		}
+		return cf.Print(os.Stdout)
+	}
+
+	diContainer, err := container.NewContainer(container.ServerConfig)
+	if err != nil {
+		return err
+	}
 	defer diContainer.Close()
 
+	// --print-graph[=dot|json] выводит граф зависимостей контейнера и завершает работу
+	if format, ok := config.PrintGraphRequested(os.Args[1:]); ok {
+		return diContainer.PrintGraph(os.Stdout, format)
+	}
+
 	var cf interfaces.ConfigServer
-	if err := diContainer.Invoke(func(c interfaces.ConfigServer) {
+	var reloader *config.Reloader
+	if err := diContainer.Invoke(func(c interfaces.ConfigServer, r *config.Reloader) {
 		cf = c
+		reloader = r
 	}); err != nil {
 		return err
 	}
 
+	// Горячая перезагрузка конфигурации по SIGHUP (и опционально по изменению файла)
+	configPath, err := config.ConfigPath(os.Args[1:], os.Environ())
+	if err != nil {
+		return err
+	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go reloader.Watch(watchCtx, configPath, cf.GetConfigWatchInterval())
+
 	var dbx *sqlx.DB
 	if err := diContainer.Invoke(func(db *sqlx.DB) {
 		dbx = db
@@ -64,33 +107,51 @@ func Run() error {
 		return errors.New("database connection is nil")
 	}
 
-	dbBase := dbx.DB
-	migrations.StartMigrations(dbBase)
-
-	// Инициализация RabbitMQ компонентов через контейнер (управление жизненным циклом)
-	if err := diContainer.Invoke(func(conn *rabbitmq.RabbitMQConnection) {}); err != nil {
+	var status interfaces.DependencyStatus
+	if err := diContainer.Invoke(func(s interfaces.DependencyStatus) {
+		status = s
+	}); err != nil {
 		return err
 	}
 
-	if err := diContainer.Invoke(func(producer *rabbitmq.RabbitMQProducer) {}); err != nil {
+	// Необязательная БД (db.required: false) может быть недоступна при старте:
+	// миграции применятся при следующем запуске
+	if status.IsUp(infra.Postgres) {
+		dbBase := dbx.DB
+		migrations.StartMigrations(dbBase)
+
+		// Миграции включённых модулей, у каждого своя таблица версий
+		if err := module.Migrate(context.Background(), dbBase, diContainer.Modules()); err != nil {
+			return err
+		}
+	} else {
+		log.Println("PostgreSQL unavailable, migrations skipped")
+	}
+
+	// Подключение к RabbitMQ. По умолчанию для сервера он необязателен
+	// (rabbitmq.required: false): при недоступном брокере сервер стартует
+	// в деградированном режиме, переподключается в фоне и сообщает об этом в /readyz
+	if err := diContainer.Invoke(func(*infra.RabbitMQConn) {}); err != nil {
 		return err
 	}
 
-	if err := diContainer.Invoke(func(consumer *rabbitmq.RabbitMQConsumer) {}); err != nil {
+	// Запуск зарегистрированных компонентов в порядке зависимостей
+	if err := diContainer.Start(context.Background()); err != nil {
 		return err
 	}
 
-	log.Println("RabbitMQ initialized successfully")
+	// Маршруты модулей со стеком middleware из конфигурации
+	handler, err := router.Handler(diContainer)
+	if err != nil {
+		return err
+	}
 
 	// Создание HTTP сервера
 	server := &http.Server{
-		Addr: cf.GetRunAddr(),
-		Handler: middleware.ChainMiddleware(
-			router.Handler(diContainer),
-			logger.WithLogging,
-		),
+		Addr:         cf.GetRunAddr(),
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		WriteTimeout: writeTimeout(cf),
 		IdleTimeout:  60 * time.Second,
 	}
 
@@ -114,20 +175,41 @@ func Run() error {
 		return err
 	case sig := <-sigChan:
 		log.Printf("Received signal: %v", sig)
+		return shutdown(server, diContainer, cf, sigChan)
+	}
+}
 
-		// Graceful shutdown с timeout в 30 секунд
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		log.Println("Shutting down server gracefully...")
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			log.Printf("Error during server shutdown: %v", err)
-			return err
-		}
-
-		log.Println("Server shut down successfully")
+// writeTimeoutMargin - запас WriteTimeout сверх дедлайна обработки на запись ответа 504
+const writeTimeoutMargin = 5 * time.Second
 
-		// Контейнер закроется автоматически через defer
-		return nil
+// writeTimeout - WriteTimeout сервера: самый длинный дедлайн middleware.timeout
+// с запасом, чтобы соединение не обрывалось раньше ответа долгого маршрута.
+// Без middleware.timeout обработка не ограничена, и запись тоже (0)
+func writeTimeout(cf interfaces.ConfigMiddleware) time.Duration {
+	if !cf.GetTimeoutEnabled() {
+		return 0
 	}
+	return middleware.MaxTimeout(cf.GetTimeoutDefault(), cf.GetTimeoutRoutes()) + writeTimeoutMargin
+}
+
+// shutdown останавливает сервер по этапам lifecycle.ServerShutdown, дедлайны
+// задаёт блок shutdown конфигурации: drain_period, http_timeout, затем
+// components_timeout и connections_timeout остановки контейнера
+func shutdown(server *http.Server, diContainer *container.Container, cf interfaces.ConfigServer, sigChan <-chan os.Signal) error {
+	return lifecycle.ServerShutdown{
+		// Без модуля healthcheck readiness пробы нет - балансировщику остаётся только ожидание
+		Drain: func() {
+			if err := diContainer.Invoke(func(health interfaces.HealthcheckService) {
+				health.Drain()
+			}); err != nil {
+				log.Printf("Readiness drain skipped: %v", err)
+			}
+		},
+		DrainPeriod: cf.GetShutdownDrainPeriod(),
+		StopServer:  server.Shutdown,
+		HTTPTimeout: cf.GetShutdownHTTPTimeout(),
+		StopContainer: func() error {
+			return diContainer.Stop(cf)
+		},
+	}.Run(sigChan)
 }
@@ -1,8 +1,11 @@
 package adapter
 
 import (
-	"github.com/SmirnovND/toolbox/pkg/rabbitmq"
+	"context"
+	"errors"
+	"github.com/SmirnovND/gobase/internal/interfaces"
 	"github.com/jmoiron/sqlx"
+	"syscall"
 )
 
 // SQLXDBCloser адаптер для *sqlx.DB
@@ -18,44 +21,37 @@ func (c *SQLXDBCloser) Close() error {
 	return c.db.Close()
 }
 
-// RabbitMQConnectionCloser адаптер для RabbitMQConnection
-type RabbitMQConnectionCloser struct {
-	conn *rabbitmq.RabbitMQConnection
-}
-
-func NewRabbitMQConnectionCloser(conn *rabbitmq.RabbitMQConnection) *RabbitMQConnectionCloser {
-	return &RabbitMQConnectionCloser{conn: conn}
-}
-
-func (c *RabbitMQConnectionCloser) Close() error {
-	c.conn.Close()
-	return nil
-}
-
-// RabbitMQProducerCloser адаптер для RabbitMQProducer
-type RabbitMQProducerCloser struct {
-	producer *rabbitmq.RabbitMQProducer
-}
-
-func NewRabbitMQProducerCloser(producer *rabbitmq.RabbitMQProducer) *RabbitMQProducerCloser {
-	return &RabbitMQProducerCloser{producer: producer}
-}
-
-func (c *RabbitMQProducerCloser) Close() error {
-	c.producer.Close()
-	return nil
-}
-
-// RabbitMQConsumerCloser адаптер для RabbitMQConsumer
-type RabbitMQConsumerCloser struct {
-	consumer *rabbitmq.RabbitMQConsumer
-}
-
-func NewRabbitMQConsumerCloser(consumer *rabbitmq.RabbitMQConsumer) *RabbitMQConsumerCloser {
-	return &RabbitMQConsumerCloser{consumer: consumer}
-}
-
-func (c *RabbitMQConsumerCloser) Close() error {
-	c.consumer.Close()
-	return nil
+// NewCloserHook - хук остановки для компонента с Close() error
+func NewCloserHook(name string, closer interfaces.Closer) interfaces.Hook {
+	return interfaces.Hook{
+		Name: name,
+		OnStop: func(ctx context.Context) error {
+			return closer.Close()
+		},
+	}
+}
+
+// NewShutdownerHook - хук остановки для компонента с Shutdown(ctx) error
+func NewShutdownerHook(name string, shutdowner interfaces.Shutdowner) interfaces.Hook {
+	return interfaces.Hook{
+		Name:   name,
+		OnStop: shutdowner.Shutdown,
+	}
+}
+
+// NewLoggerHook - хук сброса буферов логгера при остановке.
+// Логгер относится к этапу подключений, чтобы сбросить записи остальных компонентов.
+// Ошибки Sync для stdout/stderr (EINVAL, ENOTTY) игнорируются: терминал не поддерживает fsync.
+func NewLoggerHook(name string, logger interfaces.LoggerCloser) interfaces.Hook {
+	return interfaces.Hook{
+		Name:  name,
+		Stage: interfaces.StageConnection,
+		OnStop: func(ctx context.Context) error {
+			err := logger.Sync()
+			if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+				return nil
+			}
+			return err
+		},
+	}
 }
@@ -0,0 +1,203 @@
+// Package apperrors - типизированные ошибки приложения и их HTTP представление.
+// Сервисы и use cases возвращают *Error (или оборачивают его через %w),
+// контроллеры и middleware отвечают через Write - единый формат
+// application/problem+json (RFC 7807) со стабильным кодом ошибки.
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Kind - категория ошибки, определяет HTTP статус и код по умолчанию
+type Kind int
+
+const (
+	// KindInternal - непредвиденная ошибка, детали клиенту не показываются
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindMethodNotAllowed
+	KindConflict
+	KindPayloadTooLarge
+	KindRateLimited
+	KindUnavailable
+	KindTimeout
+	KindUnsupportedMediaType
+	KindNotAcceptable
+)
+
+// kindInfo - HTTP статус и стабильный код категории
+var kindInfo = map[Kind]struct {
+	status int
+	code   string
+}{
+	KindInternal:             {500, "internal"},
+	KindBadRequest:           {400, "bad_request"},
+	KindValidation:           {422, "validation_failed"},
+	KindUnauthorized:         {401, "unauthorized"},
+	KindForbidden:            {403, "forbidden"},
+	KindNotFound:             {404, "not_found"},
+	KindMethodNotAllowed:     {405, "method_not_allowed"},
+	KindConflict:             {409, "conflict"},
+	KindPayloadTooLarge:      {413, "payload_too_large"},
+	KindRateLimited:          {429, "rate_limited"},
+	KindUnavailable:          {503, "unavailable"},
+	KindTimeout:              {504, "timeout"},
+	KindUnsupportedMediaType: {415, "unsupported_media_type"},
+	KindNotAcceptable:        {406, "not_acceptable"},
+}
+
+// Status возвращает HTTP статус категории
+func (k Kind) Status() int {
+	return kindInfo[k].status
+}
+
+// Code возвращает стабильный код категории
+func (k Kind) Code() string {
+	return kindInfo[k].code
+}
+
+// FieldError - ошибка валидации одного поля
+type FieldError struct {
+	// Field - имя поля в запросе (как в JSON), для вложенных - через точку
+	Field string `json:"field"`
+	// Code - стабильный код нарушения: required, invalid, too_long...
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error - ошибка приложения. Message показывается клиенту, поэтому не должна
+// содержать внутренних деталей; причина (Err) попадает только в журнал.
+type Error struct {
+	Kind Kind
+	// Code - стабильный код для клиентов, по умолчанию - код категории
+	Code    string
+	Message string
+	// Fields - ошибки валидации по полям (KindValidation)
+	Fields []FieldError
+	// RetryAfter - через сколько повторить запрос (KindRateLimited, KindUnavailable)
+	RetryAfter time.Duration
+	// Err - исходная ошибка
+	Err error
+}
+
+// Sentinel ошибки категорий для errors.Is: errors.Is(err, apperrors.ErrNotFound)
+// истинно для любой ошибки KindNotFound независимо от кода и сообщения
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrRateLimited  = &Error{Kind: KindRateLimited}
+)
+
+// New создаёт ошибку категории kind с сообщением для клиента
+func New(kind Kind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Code: kind.Code(), Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(format string, args ...interface{}) *Error {
+	return New(KindNotFound, format, args...)
+}
+
+func Conflict(format string, args ...interface{}) *Error {
+	return New(KindConflict, format, args...)
+}
+
+func BadRequest(format string, args ...interface{}) *Error {
+	return New(KindBadRequest, format, args...)
+}
+
+func Unauthorized(format string, args ...interface{}) *Error {
+	return New(KindUnauthorized, format, args...)
+}
+
+func Forbidden(format string, args ...interface{}) *Error {
+	return New(KindForbidden, format, args...)
+}
+
+// Validation создаёт ошибку валидации с ошибками по полям
+func Validation(fields ...FieldError) *Error {
+	e := New(KindValidation, "request validation failed")
+	e.Fields = fields
+	return e
+}
+
+// RateLimited создаёт ошибку превышения лимита запросов
+func RateLimited(retryAfter time.Duration) *Error {
+	e := New(KindRateLimited, "rate limit exceeded")
+	e.RetryAfter = retryAfter
+	return e
+}
+
+// Internal оборачивает непредвиденную ошибку; клиент увидит только общее сообщение
+func Internal(err error) *Error {
+	e := New(KindInternal, "internal server error")
+	e.Err = err
+	return e
+}
+
+// WithCode возвращает копию ошибки с собственным стабильным кодом,
+// например NotFound("user not found").WithCode("user_not_found")
+func (e *Error) WithCode(code string) *Error {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// Wrap возвращает копию ошибки с исходной ошибкой err
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Kind.Code()
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сравнивает категорию и, если у target задан код, код ошибки
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Kind == e.Kind && (t.Code == "" || t.Code == e.Code)
+}
+
+// As возвращает *Error из цепочки err. Истёкший дедлайн контекста - KindTimeout,
+// превышение http.MaxBytesReader - KindPayloadTooLarge, остальные ошибки
+// без *Error в цепочке - внутренние и оборачиваются в Internal.
+func As(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytes):
+		return New(KindPayloadTooLarge, "request body too large").Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(KindTimeout, "request timeout").Wrap(err)
+	}
+	return Internal(err)
+}
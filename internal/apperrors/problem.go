@@ -0,0 +1,115 @@
+package apperrors
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/reqctx"
+	"github.com/go-chi/chi/v5"
+)
+
+// ContentType - тип ответа с ошибкой по RFC 7807
+const ContentType = "application/problem+json"
+
+// Problem - тело ответа с ошибкой (RFC 7807). Type всегда about:blank:
+// для различения ошибок клиенты используют стабильное поле Code.
+type Problem struct {
+	Type   string `json:"type" example:"about:blank"`
+	Title  string `json:"title" example:"Not Found"`
+	Status int    `json:"status" example:"404"`
+	Detail string `json:"detail,omitempty" example:"user 42 not found"`
+	// Instance - путь запроса
+	Instance string `json:"instance,omitempty" example:"/api/v1/users/42"`
+	// Code - стабильный машиночитаемый код ошибки
+	Code string `json:"code" example:"user_not_found"`
+	// TraceID - идентификатор запроса (заголовок X-Request-ID)
+	TraceID string `json:"trace_id,omitempty" example:"4f9c2d1e8a7b6c5d4e3f2a1b0c9d8e7f"`
+	// Errors - ошибки валидации по полям
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem строит тело ответа для ошибки err
+func NewProblem(r *http.Request, err error) Problem {
+	return newProblem(r, As(err))
+}
+
+func newProblem(r *http.Request, e *Error) Problem {
+	status := e.Kind.Status()
+	code := e.Code
+	if code == "" {
+		code = e.Kind.Code()
+	}
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: r.URL.Path,
+		Code:     code,
+		TraceID:  reqctx.RequestID(r.Context()),
+		Errors:   e.Fields,
+	}
+}
+
+// Write отвечает ошибкой err в формате application/problem+json.
+// Ошибки без *Error в цепочке отдаются как 500 без деталей - причину
+// должен записать в журнал вызывающий код.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	e := As(err)
+	p := newProblem(r, e)
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		// Заголовки уже отправлены - ответ не исправить, обычно клиент закрыл соединение
+		log.Printf("Failed to write error response for %s (request_id %s): %v", r.URL.Path, p.TraceID, err)
+	}
+}
+
+// NotFoundHandler - обработчик несуществующих маршрутов для chi.Router.NotFound
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, NotFound("route %s not found", r.URL.Path).WithCode("route_not_found"))
+}
+
+// MethodNotAllowedHandler - обработчик неподходящего метода для chi.Router.MethodNotAllowed.
+// Заголовок Allow перечисляет методы, для которых маршрут есть (RFC 9110):
+// chi передаёт их только своему обработчику по умолчанию, поэтому они
+// находятся заново по дереву маршрутов.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	if allowed := allowedMethods(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+	}
+	Write(w, r, New(KindMethodNotAllowed, "method %s not allowed", r.Method))
+}
+
+// routeMethods - методы, которые проверяются для заголовка Allow
+var routeMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// allowedMethods возвращает методы, для которых у пути запроса есть маршрут
+func allowedMethods(r *http.Request) []string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return nil
+	}
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+	var methods []string
+	for _, method := range routeMethods {
+		if rctx.Routes.Match(chi.NewRouteContext(), method, path) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
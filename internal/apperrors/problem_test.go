@@ -0,0 +1,153 @@
+package apperrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestKindStatusAndCode(t *testing.T) {
+	tests := []struct {
+		kind   Kind
+		status int
+		code   string
+	}{
+		{KindInternal, 500, "internal"},
+		{KindBadRequest, 400, "bad_request"},
+		{KindValidation, 422, "validation_failed"},
+		{KindUnauthorized, 401, "unauthorized"},
+		{KindForbidden, 403, "forbidden"},
+		{KindNotFound, 404, "not_found"},
+		{KindMethodNotAllowed, 405, "method_not_allowed"},
+		{KindConflict, 409, "conflict"},
+		{KindPayloadTooLarge, 413, "payload_too_large"},
+		{KindRateLimited, 429, "rate_limited"},
+		{KindUnavailable, 503, "unavailable"},
+		{KindTimeout, 504, "timeout"},
+		{KindUnsupportedMediaType, 415, "unsupported_media_type"},
+		{KindNotAcceptable, 406, "not_acceptable"},
+	}
+	if len(tests) != len(kindInfo) {
+		t.Fatalf("%d kinds covered, %d defined", len(tests), len(kindInfo))
+	}
+	for _, tt := range tests {
+		if tt.kind.Status() != tt.status || tt.kind.Code() != tt.code {
+			t.Errorf("kind %d = %d %s, want %d %s", tt.kind, tt.kind.Status(), tt.kind.Code(), tt.status, tt.code)
+		}
+	}
+}
+
+func TestAs(t *testing.T) {
+	notFound := NotFound("user 42 not found").WithCode("user_not_found")
+	tests := []struct {
+		name    string
+		err     error
+		kind    Kind
+		code    string
+		message string
+	}{
+		{"app error", notFound, KindNotFound, "user_not_found", "user 42 not found"},
+		{"wrapped app error", fmt.Errorf("get user: %w", notFound), KindNotFound, "user_not_found", "user 42 not found"},
+		{"plain error", errors.New("pq: password authentication failed"), KindInternal, "internal", "internal server error"},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), KindTimeout, "timeout", "request timeout"},
+		{"body too large", &http.MaxBytesError{Limit: 10}, KindPayloadTooLarge, "payload_too_large", "request body too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := As(tt.err)
+			if e.Kind != tt.kind || e.Code != tt.code || e.Message != tt.message {
+				t.Fatalf("As = %+v, want %d %s %q", e, tt.kind, tt.code, tt.message)
+			}
+		})
+	}
+
+	if !errors.Is(fmt.Errorf("wrap: %w", notFound), ErrNotFound) {
+		t.Fatal("errors.Is does not match the kind sentinel")
+	}
+	if errors.Is(notFound, &Error{Kind: KindNotFound, Code: "route_not_found"}) {
+		t.Fatal("errors.Is matched a different code")
+	}
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("Content-Type = %q, want %s", ct, ContentType)
+	}
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil)
+
+	rec := httptest.NewRecorder()
+	Write(rec, r, Validation(FieldError{Field: "email", Code: "required", Message: "email is required"}))
+	p := decodeProblem(t, rec)
+	if rec.Code != 422 || p.Status != 422 || p.Type != "about:blank" || p.Title != "Unprocessable Entity" {
+		t.Fatalf("status %d, problem %+v", rec.Code, p)
+	}
+	if p.Code != "validation_failed" || p.Instance != "/api/v1/users/42" || len(p.Errors) != 1 || p.Errors[0].Field != "email" {
+		t.Fatalf("problem = %+v", p)
+	}
+	if rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatal("missing X-Content-Type-Options")
+	}
+
+	// Сообщение непредвиденной ошибки не попадает к клиенту
+	rec = httptest.NewRecorder()
+	Write(rec, r, fmt.Errorf("load user: %w", errors.New("pq: password authentication failed for user app")))
+	body := rec.Body.String()
+	if strings.Contains(body, "password") {
+		t.Fatalf("internal error leaked: %s", body)
+	}
+	if p := decodeProblem(t, rec); rec.Code != 500 || p.Code != "internal" || p.Detail != "internal server error" {
+		t.Fatalf("status %d, problem %+v", rec.Code, p)
+	}
+
+	rec = httptest.NewRecorder()
+	Write(rec, r, RateLimited(1500*time.Millisecond))
+	if rec.Code != 429 || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestRouteHandlers(t *testing.T) {
+	r := chi.NewRouter()
+	r.MethodNotAllowed(MethodNotAllowedHandler)
+	r.NotFound(NotFoundHandler)
+	r.Route("/api/v1/users", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		r.Patch("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/users/42", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, PATCH, DELETE" {
+		t.Fatalf("Allow = %q, want GET, PATCH, DELETE", allow)
+	}
+	if p := decodeProblem(t, rec); p.Code != "method_not_allowed" {
+		t.Fatalf("problem = %+v", p)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil))
+	if p := decodeProblem(t, rec); rec.Code != http.StatusNotFound || p.Code != "route_not_found" {
+		t.Fatalf("status %d, problem %+v", rec.Code, p)
+	}
+}
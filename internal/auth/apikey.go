@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// SchemeAPIKey - схема заголовка Authorization: ApiKey <ключ>
+const SchemeAPIKey = "ApiKey"
+
+// apiKeyTag - начало каждого ключа: ключ узнаётся в журналах и сканерами секретов
+const apiKeyTag = "gbk_"
+
+// GenerateAPIKey создаёт новый API ключ "gbk_<prefix>_<secret>". Prefix
+// (вместе с gbk_) хранится открыто и показывается в списках ключей, hash -
+// SHA-256 всего ключа; сам ключ не хранится и отдаётся клиенту один раз.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	buf := make([]byte, 6+32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	prefix = apiKeyTag + hex.EncodeToString(buf[:6])
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[6:])
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// HashAPIKey - хеш ключа для поиска в хранилище. 256 бит случайного секрета
+// не подбираются перебором, поэтому медленный KDF не нужен.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
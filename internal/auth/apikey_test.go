@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(prefix, apiKeyTag) || !strings.HasPrefix(key, prefix+"_") {
+		t.Fatalf("key %q does not start with prefix %q", key, prefix)
+	}
+	if hash != HashAPIKey(key) || len(hash) != 64 {
+		t.Fatalf("hash %q does not match the key", hash)
+	}
+	if HashAPIKey(key+"x") == hash {
+		t.Fatal("different keys have the same hash")
+	}
+
+	other, otherPrefix, _, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key || otherPrefix == prefix {
+		t.Fatal("generated keys are not unique")
+	}
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Authenticator проверяет учётные данные одной схемы заголовка
+// Authorization (Bearer, ApiKey) и возвращает субъекта запроса.
+// Неверные учётные данные - ошибка apperrors 401.
+type Authenticator interface {
+	Scheme() string
+	Authenticate(ctx context.Context, credentials string) (*Principal, error)
+}
+
+// ParseAuthorization разбирает заголовок Authorization вида "<схема> <учётные данные>"
+func ParseAuthorization(r *http.Request) (scheme, credentials string, ok bool) {
+	scheme, credentials, ok = strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	credentials = strings.TrimSpace(credentials)
+	return scheme, credentials, ok && scheme != "" && credentials != ""
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Алгоритмы подписи JWT
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// JWTConfig - настройки проверки JWT
+type JWTConfig struct {
+	// Algorithms - допустимые алгоритмы подписи, токены с другим alg отклоняются
+	Algorithms []string
+	// Secret - ключ HS256
+	Secret []byte
+	// PublicKeyFiles - PEM файлы открытых ключей RS256/EdDSA: "path" или "kid=path"
+	PublicKeyFiles []string
+	// JWKSFile - локальный JWKS (RFC 7517) с ключами RSA, OKP (Ed25519) и oct
+	JWKSFile string
+	// Issuer и Audience - ожидаемые iss и aud, пустые - не проверяются
+	Issuer   string
+	Audience string
+	// Leeway - допуск расхождения часов для exp, nbf и iat
+	Leeway time.Duration
+}
+
+// jwtAuthenticator - схема Bearer с JWT. Ключ выбирается по kid из заголовка
+// токена, без kid проверяются все ключи, подходящие алгоритму.
+type jwtAuthenticator struct {
+	parser *jwt.Parser
+	keys   *keySet
+}
+
+// NewJWTAuthenticator загружает ключи из конфигурации и файлов и возвращает
+// Authenticator схемы Bearer
+func NewJWTAuthenticator(cfg JWTConfig) (Authenticator, error) {
+	keys := newKeySet()
+	if len(cfg.Secret) > 0 {
+		keys.add("", cfg.Secret)
+	}
+	for _, entry := range cfg.PublicKeyFiles {
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok {
+			kid, path = "", entry
+		}
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("jwt public key %s: %w", path, err)
+		}
+		keys.add(kid, key)
+	}
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+		if err := keys.addJWKS(data); err != nil {
+			return nil, fmt.Errorf("jwks %s: %w", cfg.JWKSFile, err)
+		}
+	}
+	for _, alg := range cfg.Algorithms {
+		if len(keys.forAlg(alg, "")) == 0 {
+			return nil, fmt.Errorf("jwt: no keys for algorithm %s", alg)
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &jwtAuthenticator{parser: jwt.NewParser(opts...), keys: keys}, nil
+}
+
+func (a *jwtAuthenticator) Scheme() string {
+	return "Bearer"
+}
+
+func (a *jwtAuthenticator) Authenticate(_ context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(token, claims, a.keyFunc); err != nil {
+		return nil, apperrors.Unauthorized("invalid or expired token").WithCode("invalid_token").Wrap(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, apperrors.Unauthorized("token has no subject").WithCode("invalid_token")
+	}
+	p := &Principal{
+		Kind:    KindUser,
+		Subject: subject,
+		Scopes:  claimStrings(claims, "scope", "scp"),
+		Roles:   claimStrings(claims, "roles"),
+		Claims:  claims,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		p.ExpiresAt = exp.Time
+	}
+	return p, nil
+}
+
+// keyFunc возвращает ключи, подходящие alg и kid токена
+func (a *jwtAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	keys := a.keys.forAlg(token.Method.Alg(), kid)
+	switch len(keys) {
+	case 0:
+		return nil, fmt.Errorf("no key for alg %s and kid %q", token.Method.Alg(), kid)
+	case 1:
+		return keys[0], nil
+	}
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+// claimStrings читает список строк из первого найденного claim: строка
+// через пробел (scope по RFC 8693) или массив строк
+func claimStrings(claims jwt.MapClaims, names ...string) []string {
+	for _, name := range names {
+		switch v := claims[name].(type) {
+		case string:
+			return strings.Fields(v)
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+func loadPublicKey(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("expected an RSA or Ed25519 public key in PEM")
+}
+
+// keySet - ключи проверки подписи по kid; ключи без kid - под пустым kid
+type keySet struct {
+	byKid map[string][]interface{}
+}
+
+func newKeySet() *keySet {
+	return &keySet{byKid: make(map[string][]interface{})}
+}
+
+func (s *keySet) add(kid string, key interface{}) {
+	s.byKid[kid] = append(s.byKid[kid], key)
+}
+
+// forAlg - ключи, тип которых подходит alg: с указанным kid, для неизвестного
+// kid - ключи без kid, для пустого kid - все ключи
+func (s *keySet) forAlg(alg, kid string) []jwt.VerificationKey {
+	var candidates []interface{}
+	if keys, ok := s.byKid[kid]; ok && kid != "" {
+		candidates = keys
+	} else if kid != "" {
+		candidates = s.byKid[""]
+	} else {
+		for _, keys := range s.byKid {
+			candidates = append(candidates, keys...)
+		}
+	}
+
+	var keys []jwt.VerificationKey
+	for _, key := range candidates {
+		if keyMatchesAlg(key, alg) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// keyMatchesAlg не даёт проверить, например, HS256 подпись открытым RSA ключом
+func keyMatchesAlg(key interface{}, alg string) bool {
+	switch key.(type) {
+	case []byte:
+		return alg == AlgHS256
+	case *rsa.PublicKey:
+		return alg == AlgRS256
+	case ed25519.PublicKey:
+		return alg == AlgEdDSA
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var hmacSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// jwtKeys - ключи подписи тестовых токенов и файлы их открытых ключей
+type jwtKeys struct {
+	rsa     *rsa.PrivateKey
+	rsaPEM  []byte
+	rsaFile string
+	ed      ed25519.PrivateKey
+	edFile  string
+}
+
+func newJWTKeys(t *testing.T) *jwtKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	k := &jwtKeys{rsa: rsaKey, ed: edKey}
+	k.rsaPEM = publicKeyPEM(t, &rsaKey.PublicKey)
+	k.rsaFile = writeFile(t, dir, "rsa.pem", k.rsaPEM)
+	k.edFile = writeFile(t, dir, "ed.pem", publicKeyPEM(t, edPub))
+	return k
+}
+
+func publicKeyPEM(t *testing.T, key interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// sign подписывает claims; пустой kid не попадает в заголовок
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// validClaims - claims, которые проходят проверку с iss "issuer" и aud "api"
+func validClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":   "42",
+		"iss":   "issuer",
+		"aud":   "api",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"scope": "users:read users:write",
+		"roles": []string{"admin"},
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	keys := newJWTKeys(t)
+	now := time.Now()
+	a, err := NewJWTAuthenticator(JWTConfig{
+		Algorithms:     []string{AlgHS256, AlgRS256, AlgEdDSA},
+		Secret:         hmacSecret,
+		PublicKeyFiles: []string{keys.rsaFile, "ed-1=" + keys.edFile},
+		Issuer:         "issuer",
+		Audience:       "api",
+		Leeway:         30 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"hs256", sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(nil)), true},
+		{"rs256", sign(t, jwt.SigningMethodRS256, keys.rsa, "", validClaims(nil)), true},
+		{"eddsa by kid", sign(t, jwt.SigningMethodEdDSA, keys.ed, "ed-1", validClaims(nil)), true},
+		{"eddsa without kid", sign(t, jwt.SigningMethodEdDSA, keys.ed, "", validClaims(nil)), true},
+		// Неизвестный kid проверяется ключами без kid
+		{"unknown kid falls back", sign(t, jwt.SigningMethodRS256, keys.rsa, "rotated", validClaims(nil)), true},
+		{"unknown kid without key", sign(t, jwt.SigningMethodEdDSA, keys.ed, "rotated", validClaims(nil)), false},
+		{"wrong rsa key", sign(t, jwt.SigningMethodRS256, otherRSA, "", validClaims(nil)), false},
+		// Открытый RSA ключ не должен приниматься как секрет HMAC
+		{"alg confusion", sign(t, jwt.SigningMethodHS256, keys.rsaPEM, "", validClaims(nil)), false},
+		{"alg none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims(nil)), false},
+		{"expired within leeway", sign(t, jwt.SigningMethodHS256, hmacSecret, "",
+			validClaims(jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()})), true},
+		{"expired", sign(t, jwt.SigningMethodHS256, hmacSecret, "",
+			validClaims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})), false},
+		{"no exp", sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(jwt.MapClaims{"exp": nil})), false},
+		{"nbf within leeway", sign(t, jwt.SigningMethodHS256, hmacSecret, "",
+			validClaims(jwt.MapClaims{"nbf": now.Add(10 * time.Second).Unix()})), true},
+		{"not yet valid", sign(t, jwt.SigningMethodHS256, hmacSecret, "",
+			validClaims(jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()})), false},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(jwt.MapClaims{"iss": "other"})), false},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(jwt.MapClaims{"aud": "other"})), false},
+		{"no subject", sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(jwt.MapClaims{"sub": nil})), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tt.token)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("token accepted, principal %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Kind != KindUser || p.Subject != "42" || p.ExpiresAt.IsZero() {
+				t.Fatalf("principal = %+v", p)
+			}
+			if strings.Join(p.Scopes, " ") != "users:read users:write" || strings.Join(p.Roles, ",") != "admin" {
+				t.Fatalf("scopes %v, roles %v", p.Scopes, p.Roles)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorAlgorithms(t *testing.T) {
+	keys := newJWTKeys(t)
+
+	// Алгоритм без подходящего ключа - ошибка конфигурации
+	if _, err := NewJWTAuthenticator(JWTConfig{Algorithms: []string{AlgRS256}, Secret: hmacSecret}); err == nil {
+		t.Fatal("RS256 without public keys accepted")
+	}
+
+	// Токен с алгоритмом вне списка отклоняется, даже если ключ для него есть
+	a, err := NewJWTAuthenticator(JWTConfig{
+		Algorithms:     []string{AlgRS256},
+		Secret:         hmacSecret,
+		PublicKeyFiles: []string{keys.rsaFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(context.Background(), sign(t, jwt.SigningMethodHS256, hmacSecret, "", validClaims(nil))); err == nil {
+		t.Fatal("HS256 token accepted with RS256 only")
+	}
+}
+
+func jwkRSA(kid, use string, key *rsa.PublicKey) string {
+	return fmt.Sprintf(`{"kty":"RSA","kid":%q,"use":%q,"n":%q,"e":%q}`, kid, use,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+}
+
+func TestAddJWKS(t *testing.T) {
+	keys := newJWTKeys(t)
+	edPub := keys.ed.Public().(ed25519.PublicKey)
+	rsaJWK := jwkRSA("rsa-1", "sig", &keys.rsa.PublicKey)
+	edJWK := fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","kid":"ed-1","x":%q}`, base64.RawURLEncoding.EncodeToString(edPub))
+	octJWK := fmt.Sprintf(`{"kty":"oct","kid":"hs-1","k":%q}`, base64.RawURLEncoding.EncodeToString(hmacSecret))
+
+	tests := []struct {
+		name string
+		jwks string
+		// kids - kid загруженных ключей, nil - ожидается ошибка
+		kids []string
+	}{
+		{"all key types", `{"keys":[` + rsaJWK + `,` + edJWK + `,` + octJWK + `]}`, []string{"rsa-1", "ed-1", "hs-1"}},
+		{"skips encryption keys", `{"keys":[` + jwkRSA("enc-1", "enc", &keys.rsa.PublicKey) + `,` + edJWK + `]}`, []string{"ed-1"}},
+		{"malformed json", `{"keys":[`, nil},
+		{"unsupported kty", `{"keys":[{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}]}`, nil},
+		{"unsupported curve", `{"keys":[{"kty":"OKP","crv":"X25519","x":"AA"}]}`, nil},
+		{"short ed25519 key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AAAA"}]}`, nil},
+		{"bad base64", `{"keys":[{"kty":"RSA","n":"***","e":"AQAB"}]}`, nil},
+		{"huge exponent", `{"keys":[{"kty":"RSA","n":"AQAB","e":"AQAAAAAAAAAA"}]}`, nil},
+		{"empty oct", `{"keys":[{"kty":"oct","k":""}]}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newKeySet()
+			err := s.addJWKS([]byte(tt.jwks))
+			if tt.kids == nil {
+				if err == nil {
+					t.Fatalf("jwks accepted, keys %v", s.byKid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s.byKid) != len(tt.kids) {
+				t.Fatalf("loaded kids %v, want %v", s.byKid, tt.kids)
+			}
+			for _, kid := range tt.kids {
+				if len(s.byKid[kid]) != 1 {
+					t.Fatalf("kid %s not loaded: %v", kid, s.byKid)
+				}
+			}
+		})
+	}
+
+	// Токены проверяются ключами из файла JWKS с выбором по kid
+	path := writeFile(t, t.TempDir(), "jwks.json", []byte(`{"keys":[`+rsaJWK+`,`+edJWK+`]}`))
+	a, err := NewJWTAuthenticator(JWTConfig{Algorithms: []string{AlgRS256, AlgEdDSA}, JWKSFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{
+		sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", validClaims(nil)),
+		sign(t, jwt.SigningMethodEdDSA, keys.ed, "ed-1", validClaims(nil)),
+	} {
+		if _, err := a.Authenticate(context.Background(), token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// kid указывает на ключ другого типа - подходящих ключей нет
+	if _, err := a.Authenticate(context.Background(), sign(t, jwt.SigningMethodEdDSA, keys.ed, "rsa-1", validClaims(nil))); err == nil {
+		t.Fatal("EdDSA token accepted with RSA kid")
+	}
+}
+
+func TestKeyMatchesAlg(t *testing.T) {
+	keys := newJWTKeys(t)
+	for _, tt := range []struct {
+		key interface{}
+		alg string
+	}{
+		{hmacSecret, AlgHS256},
+		{&keys.rsa.PublicKey, AlgRS256},
+		{keys.ed.Public().(ed25519.PublicKey), AlgEdDSA},
+	} {
+		for _, alg := range []string{AlgHS256, AlgRS256, AlgEdDSA, "none"} {
+			if got := keyMatchesAlg(tt.key, alg); got != (alg == tt.alg) {
+				t.Fatalf("keyMatchesAlg(%T, %s) = %v", tt.key, alg, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package auth - аутентификация запросов: субъект запроса (Principal) в контексте,
+// проверка JWT (HS256, RS256, EdDSA), формат API ключей и интерфейс Authenticator
+// для схем заголовка Authorization. HTTP middleware - internal/middleware.Authenticate,
+// RequireAuth и RequireScope; use cases читают субъекта через PrincipalFrom.
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/reqctx"
+)
+
+// Виды субъектов
+const (
+	KindUser   = "user"
+	KindAPIKey = "api_key"
+)
+
+// Principal - аутентифицированный субъект запроса
+type Principal struct {
+	// Kind - вид субъекта: KindUser или KindAPIKey
+	Kind string
+	// Subject - идентификатор субъекта (claim sub токена, ID API ключа)
+	Subject string
+	Scopes  []string
+	Roles   []string
+	// ExpiresAt - срок действия учётных данных, нулевой - бессрочно
+	ExpiresAt time.Time
+	// Claims - claims JWT; nil для других схем
+	Claims map[string]interface{}
+}
+
+// HasScope сообщает, выдан ли субъекту scope
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// HasRole сообщает, есть ли у субъекта роль
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type principalKey struct{}
+
+// WithPrincipal возвращает контекст с субъектом запроса. Идентификатор
+// субъекта также доступен через reqctx.Subject (журналы, rate limiting);
+// для API ключей - с видом субъекта (api_key:17), чтобы не совпадать с ID пользователей.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	subject := p.Subject
+	if p.Kind != KindUser {
+		subject = p.Kind + ":" + subject
+	}
+	ctx = reqctx.WithSubject(ctx, subject)
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom возвращает субъекта запроса; false - запрос анонимный
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// RequirePrincipal возвращает субъекта запроса или apperrors 401 для анонимного запроса
+func RequirePrincipal(ctx context.Context) (*Principal, error) {
+	p, ok := PrincipalFrom(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized("authentication required")
+	}
+	return p, nil
+}
+
+// RequireScope проверяет, что у субъекта запроса есть все scopes:
+// 401 для анонимного запроса, 403 с кодом insufficient_scope при нехватке
+func RequireScope(ctx context.Context, scopes ...string) error {
+	p, err := RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		if !p.HasScope(scope) {
+			return apperrors.Forbidden("scope %q required", scope).WithCode("insufficient_scope")
+		}
+	}
+	return nil
+}
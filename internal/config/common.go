@@ -0,0 +1,344 @@
+package config
+
+import "time"
+
+// Общие блоки конфигурации, которые встраиваются в конфигурации бинарников
+// (internal/config/server, internal/config/consumer, internal/config/cron).
+// Тег reload:"restart" - значение не меняется при горячей перезагрузке.
+// Ключ <key>_file задаёт путь к файлу, из которого читается значение <key>.
+
+type Db struct {
+	Dsn          Secret `yaml:"dsn" reload:"restart"`
+	DsnFile      string `yaml:"dsn_file" reload:"restart"`
+	MaxOpenConns int    `yaml:"max_open_conns"`
+	MaxIdleConns int    `yaml:"max_idle_conns"`
+	// ConnMaxLifetime - максимальное время жизни соединения, 0 - без ограничения
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// ConnMaxIdleTime - максимальное время простоя соединения в пуле, 0 - без ограничения
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	// ConnectTimeout - таймаут установки соединения (connect_timeout в DSN), 0 - без ограничения
+	ConnectTimeout time.Duration `yaml:"connect_timeout" reload:"restart"`
+	// StatementTimeout - таймаут выполнения запроса на стороне PostgreSQL
+	// (statement_timeout сессии), 0 - значение сервера
+	StatementTimeout time.Duration `yaml:"statement_timeout" reload:"restart"`
+	// Required - без PostgreSQL бинарник не стартует; иначе стартует
+	// в деградированном режиме и переподключается в фоне
+	Required bool `yaml:"required" reload:"restart"`
+	// ReconnectInterval - период повторных попыток подключения в деградированном режиме
+	ReconnectInterval time.Duration `yaml:"reconnect_interval" reload:"restart"`
+}
+
+type RabbitMQ struct {
+	URL     Secret `yaml:"url" reload:"restart"`
+	URLFile string `yaml:"url_file" reload:"restart"`
+	// Required - без RabbitMQ бинарник не стартует; иначе стартует
+	// в деградированном режиме и переподключается в фоне
+	Required bool `yaml:"required" reload:"restart"`
+	// ReconnectInterval - период попыток переподключения после разрыва или неудачного старта
+	ReconnectInterval time.Duration `yaml:"reconnect_interval" reload:"restart"`
+}
+
+type Log struct {
+	Level string `yaml:"level"`
+}
+
+type Health struct {
+	// CheckTimeout - таймаут одной проверки, если проверка не задала свой
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	// DiskPath - путь, свободное место на разделе которого проверяется
+	DiskPath string `yaml:"disk_path"`
+	// DiskMinFreeMB - минимум свободного места в мегабайтах, 0 - проверка отключена
+	DiskMinFreeMB int `yaml:"disk_min_free_mb"`
+	// RefreshInterval - период фонового выполнения проверок; пробы отдают
+	// последний результат. 0 - проверки выполняются на каждый запрос
+	RefreshInterval time.Duration `yaml:"refresh_interval" reload:"restart"`
+	// MaxAgeIntervals - через сколько периодов RefreshInterval сохранённый результат
+	// устаревает: readiness и startup пробы отвечают down. 0 - без ограничения
+	MaxAgeIntervals int `yaml:"max_age_intervals" reload:"restart"`
+	// FreshInterval - не чаще какого периода ?fresh=1 выполняет проверки пробы,
+	// остальные запросы получают последний результат. 0 - без ограничения
+	FreshInterval time.Duration `yaml:"fresh_interval" reload:"restart"`
+}
+
+type Shutdown struct {
+	// DrainPeriod - время между провалом readiness и остановкой HTTP сервера,
+	// за которое балансировщик перестаёт направлять запросы (только cmd/server)
+	DrainPeriod time.Duration `yaml:"drain_period"`
+	// HTTPTimeout - дедлайн завершения активных HTTP запросов (только cmd/server)
+	HTTPTimeout time.Duration `yaml:"http_timeout"`
+	// ComponentsTimeout - дедлайн остановки consumers, producers и фоновых задач
+	ComponentsTimeout time.Duration `yaml:"components_timeout"`
+	// ConnectionsTimeout - дедлайн закрытия подключений к PostgreSQL и RabbitMQ
+	ConnectionsTimeout time.Duration `yaml:"connections_timeout"`
+}
+
+type RBAC struct {
+	// CacheTTL - сколько реплика хранит разрешения субъекта. Изменения через
+	// API управления сбрасывают кеш сразу на своей реплике, на остальных - через CacheTTL.
+	// 0 - без кеша
+	CacheTTL time.Duration `yaml:"cache_ttl" reload:"restart"`
+	// TrustTokenRoles - выдавать субъекту роли из claim roles токена. Любой, кто
+	// выпускает принимаемые токены, получает права этих ролей, поэтому по умолчанию выключено
+	TrustTokenRoles bool `yaml:"trust_token_roles" reload:"restart"`
+}
+
+type Modules struct {
+	// Disabled - имена модулей (internal/modules), которые не загружаются
+	Disabled []string `yaml:"disabled" reload:"restart"`
+}
+
+// DefaultDb - значения по умолчанию для блока db
+func DefaultDb() Db {
+	return Db{
+		MaxOpenConns:      25,
+		MaxIdleConns:      5,
+		ConnMaxLifetime:   30 * time.Minute,
+		ConnMaxIdleTime:   5 * time.Minute,
+		ConnectTimeout:    5 * time.Second,
+		Required:          true,
+		ReconnectInterval: 5 * time.Second,
+	}
+}
+
+// DefaultRabbitMQ - значения по умолчанию для блока rabbitmq
+func DefaultRabbitMQ() RabbitMQ {
+	return RabbitMQ{
+		Required:          true,
+		ReconnectInterval: 5 * time.Second,
+	}
+}
+
+// DefaultHealth - значения по умолчанию для блока health
+func DefaultHealth() Health {
+	return Health{
+		CheckTimeout:    2 * time.Second,
+		DiskPath:        "/",
+		DiskMinFreeMB:   100,
+		RefreshInterval: 10 * time.Second,
+		MaxAgeIntervals: 3,
+		FreshInterval:   time.Second,
+	}
+}
+
+// DefaultShutdown - значения по умолчанию для блока shutdown
+func DefaultShutdown() Shutdown {
+	return Shutdown{
+		DrainPeriod:        5 * time.Second,
+		HTTPTimeout:        15 * time.Second,
+		ComponentsTimeout:  10 * time.Second,
+		ConnectionsTimeout: 5 * time.Second,
+	}
+}
+
+// DefaultRBAC - значения по умолчанию для блока rbac
+func DefaultRBAC() RBAC {
+	return RBAC{
+		CacheTTL: 30 * time.Second,
+	}
+}
+
+// DefaultLog - значения по умолчанию для блока log
+func DefaultLog() Log {
+	return Log{
+		Level: "info",
+	}
+}
+
+func (d *Db) GetDBDsn() string {
+	return string(d.Dsn)
+}
+
+func (d *Db) GetDBMaxOpenConns() int {
+	return d.MaxOpenConns
+}
+
+func (d *Db) GetDBMaxIdleConns() int {
+	return d.MaxIdleConns
+}
+
+func (d *Db) GetDBConnMaxLifetime() time.Duration {
+	return d.ConnMaxLifetime
+}
+
+func (d *Db) GetDBConnMaxIdleTime() time.Duration {
+	return d.ConnMaxIdleTime
+}
+
+func (d *Db) GetDBConnectTimeout() time.Duration {
+	return d.ConnectTimeout
+}
+
+func (d *Db) GetDBStatementTimeout() time.Duration {
+	return d.StatementTimeout
+}
+
+func (d *Db) GetDBRequired() bool {
+	return d.Required
+}
+
+func (d *Db) GetDBReconnectInterval() time.Duration {
+	return d.ReconnectInterval
+}
+
+func (r *RabbitMQ) GetRabbitMQURL() string {
+	return string(r.URL)
+}
+
+func (r *RabbitMQ) GetRabbitMQRequired() bool {
+	return r.Required
+}
+
+func (r *RabbitMQ) GetRabbitMQReconnectInterval() time.Duration {
+	return r.ReconnectInterval
+}
+
+func (l *Log) GetLogLevel() string {
+	return l.Level
+}
+
+func (h *Health) GetHealthCheckTimeout() time.Duration {
+	return h.CheckTimeout
+}
+
+func (h *Health) GetHealthDiskPath() string {
+	return h.DiskPath
+}
+
+func (h *Health) GetHealthDiskMinFreeMB() int {
+	return h.DiskMinFreeMB
+}
+
+func (h *Health) GetHealthRefreshInterval() time.Duration {
+	return h.RefreshInterval
+}
+
+func (h *Health) GetHealthMaxAgeIntervals() int {
+	return h.MaxAgeIntervals
+}
+
+func (h *Health) GetHealthFreshInterval() time.Duration {
+	return h.FreshInterval
+}
+
+func (s *Shutdown) GetShutdownDrainPeriod() time.Duration {
+	return s.DrainPeriod
+}
+
+func (s *Shutdown) GetShutdownHTTPTimeout() time.Duration {
+	return s.HTTPTimeout
+}
+
+func (s *Shutdown) GetShutdownComponentsTimeout() time.Duration {
+	return s.ComponentsTimeout
+}
+
+func (s *Shutdown) GetShutdownConnectionsTimeout() time.Duration {
+	return s.ConnectionsTimeout
+}
+
+func (m *Modules) GetDisabledModules() []string {
+	return m.Disabled
+}
+
+// Validate проверяет блок db
+func (d *Db) Validate(v *Validator) {
+	if d.Dsn == "" {
+		v.Add("db.dsn", "is required")
+	}
+	if d.MaxOpenConns < 0 {
+		v.Add("db.max_open_conns", "must be >= 0, got %d", d.MaxOpenConns)
+	}
+	if d.MaxIdleConns < 0 {
+		v.Add("db.max_idle_conns", "must be >= 0, got %d", d.MaxIdleConns)
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		v.Add("db.max_idle_conns", "must not exceed db.max_open_conns (%d > %d)",
+			d.MaxIdleConns, d.MaxOpenConns)
+	}
+	for _, t := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"db.conn_max_lifetime", d.ConnMaxLifetime},
+		{"db.conn_max_idle_time", d.ConnMaxIdleTime},
+		{"db.connect_timeout", d.ConnectTimeout},
+		{"db.statement_timeout", d.StatementTimeout},
+	} {
+		if t.value < 0 {
+			v.Add(t.key, "must be >= 0, got %s", t.value)
+		}
+	}
+	if d.ReconnectInterval <= 0 {
+		v.Add("db.reconnect_interval", "must be > 0, got %s", d.ReconnectInterval)
+	}
+}
+
+// Validate проверяет блок rabbitmq
+func (r *RabbitMQ) Validate(v *Validator) {
+	ValidateAMQPURL(v, "rabbitmq.url", string(r.URL))
+	if r.ReconnectInterval <= 0 {
+		v.Add("rabbitmq.reconnect_interval", "must be > 0, got %s", r.ReconnectInterval)
+	}
+}
+
+// Validate проверяет блок log
+func (l *Log) Validate(v *Validator) {
+	switch l.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		v.Add("log.level", "must be one of debug, info, warn, error, got %q", l.Level)
+	}
+}
+
+// Validate проверяет блок health
+func (h *Health) Validate(v *Validator) {
+	if h.CheckTimeout <= 0 {
+		v.Add("health.check_timeout", "must be > 0, got %s", h.CheckTimeout)
+	}
+	if h.DiskMinFreeMB < 0 {
+		v.Add("health.disk_min_free_mb", "must be >= 0, got %d", h.DiskMinFreeMB)
+	}
+	if h.DiskMinFreeMB > 0 && h.DiskPath == "" {
+		v.Add("health.disk_path", "is required when health.disk_min_free_mb is set")
+	}
+	if h.RefreshInterval < 0 {
+		v.Add("health.refresh_interval", "must be >= 0, got %s", h.RefreshInterval)
+	}
+	if h.MaxAgeIntervals < 0 {
+		v.Add("health.max_age_intervals", "must be >= 0, got %d", h.MaxAgeIntervals)
+	}
+	if h.FreshInterval < 0 {
+		v.Add("health.fresh_interval", "must be >= 0, got %s", h.FreshInterval)
+	}
+}
+
+func (r *RBAC) GetRBACCacheTTL() time.Duration {
+	return r.CacheTTL
+}
+
+func (r *RBAC) GetRBACTrustTokenRoles() bool {
+	return r.TrustTokenRoles
+}
+
+// Validate проверяет блок rbac
+func (r *RBAC) Validate(v *Validator) {
+	if r.CacheTTL < 0 {
+		v.Add("rbac.cache_ttl", "must be >= 0, got %s", r.CacheTTL)
+	}
+}
+
+// Validate проверяет блок shutdown
+func (s *Shutdown) Validate(v *Validator) {
+	if s.DrainPeriod < 0 {
+		v.Add("shutdown.drain_period", "must be >= 0, got %s", s.DrainPeriod)
+	}
+	if s.HTTPTimeout <= 0 {
+		v.Add("shutdown.http_timeout", "must be > 0, got %s", s.HTTPTimeout)
+	}
+	if s.ComponentsTimeout <= 0 {
+		v.Add("shutdown.components_timeout", "must be > 0, got %s", s.ComponentsTimeout)
+	}
+	if s.ConnectionsTimeout <= 0 {
+		v.Add("shutdown.connections_timeout", "must be > 0, got %s", s.ConnectionsTimeout)
+	}
+}
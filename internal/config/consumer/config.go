@@ -0,0 +1,111 @@
+package config
+
+import (
+	"github.com/SmirnovND/gobase/internal/config"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"io"
+	"os"
+)
+
+// Config - конфигурация RabbitMQ consumer (cmd/crons/rabbitmq_consumer).
+// Общие блоки db, rabbitmq и log описаны в internal/config.
+type Config struct {
+	config.Db       `yaml:"db"`
+	config.RabbitMQ `yaml:"rabbitmq"`
+	config.Log      `yaml:"log"`
+	config.Health   `yaml:"health"`
+	config.Shutdown `yaml:"shutdown"`
+	config.Modules  `yaml:"modules"`
+	config.RBAC     `yaml:"rbac"`
+	Consumer        `yaml:"consumer"`
+}
+
+type Consumer struct {
+	// Queue - имя очереди, из которой читаются сообщения
+	Queue string `yaml:"queue" reload:"restart"`
+	// Prefetch - сколько неподтверждённых сообщений брокер отдаёт consumer'у одновременно
+	Prefetch int `yaml:"prefetch" reload:"restart"`
+}
+
+// defaultConfig - значения по умолчанию, самый низкий приоритет
+func defaultConfig() *Config {
+	return &Config{
+		Db:       config.DefaultDb(),
+		RabbitMQ: config.DefaultRabbitMQ(),
+		Log:      config.DefaultLog(),
+		Health:   config.DefaultHealth(),
+		Shutdown: config.DefaultShutdown(),
+		RBAC:     config.DefaultRBAC(),
+		Consumer: Consumer{
+			Prefetch: 10,
+		},
+	}
+}
+
+func (c *Config) GetConsumerQueue() string {
+	return c.Consumer.Queue
+}
+
+func (c *Config) GetConsumerPrefetch() int {
+	return c.Consumer.Prefetch
+}
+
+// Validate проверяет обязательные поля и форматы значений.
+// Возвращает *config.ValidationError со списком всех нарушений или nil.
+func (c *Config) Validate() error {
+	v := &config.Validator{}
+	c.Db.Validate(v)
+	c.RabbitMQ.Validate(v)
+	c.Log.Validate(v)
+	c.Health.Validate(v)
+	c.Shutdown.Validate(v)
+	c.RBAC.Validate(v)
+	if c.Consumer.Queue == "" {
+		v.Add("consumer.queue", "is required")
+	}
+	if c.Consumer.Prefetch < 0 {
+		v.Add("consumer.prefetch", "must be >= 0, got %d", c.Consumer.Prefetch)
+	}
+	return v.Err()
+}
+
+// Print выводит итоговую конфигурацию с замаскированными секретами
+func (c *Config) Print(w io.Writer) error {
+	return config.Print(w, c)
+}
+
+// Load собирает конфигурацию consumer, порядок источников описан в config.Load
+func Load(args []string, environ []string) (*Config, error) {
+	cf := defaultConfig()
+	if err := config.Load(cf, args, environ); err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// NewConfig собирает конфигурацию из аргументов командной строки и окружения
+// и проверяет её
+func NewConfig() (interfaces.ConfigConsumer, error) {
+	cf, err := Load(os.Args[1:], os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cf, nil
+}
+
+// NewReloader создаёт config.Reloader, перечитывающий конфигурацию consumer
+func NewReloader(cf interfaces.ConfigConsumer) *config.Reloader {
+	current, ok := cf.(*Config)
+	if !ok {
+		return config.NewReloader(nil, nil)
+	}
+	args := append([]string(nil), os.Args[1:]...)
+	return config.NewReloader(current, func() (config.Reloadable, error) {
+		return Load(args, os.Environ())
+	})
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"github.com/SmirnovND/gobase/internal/config"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"io"
+	"os"
+	"time"
+)
+
+// Config - конфигурация крон скриптов (cmd/crons/*).
+// Общие блоки db, rabbitmq и log описаны в internal/config.
+type Config struct {
+	config.Db       `yaml:"db"`
+	config.RabbitMQ `yaml:"rabbitmq"`
+	config.Log      `yaml:"log"`
+	config.Health   `yaml:"health"`
+	config.Shutdown `yaml:"shutdown"`
+	config.Modules  `yaml:"modules"`
+	config.RBAC     `yaml:"rbac"`
+	Cron            `yaml:"cron"`
+}
+
+type Cron struct {
+	// Schedule - период запуска задачи. 0 - выполнить один раз и завершиться
+	// (расписание задаётся внешним планировщиком: crontab, Kubernetes CronJob)
+	Schedule time.Duration `yaml:"schedule"`
+	// Timeout - максимальная длительность одного запуска, 0 - без ограничения
+	Timeout time.Duration `yaml:"timeout"`
+	// Job - имя задачи модуля (module.Job), пусто - ExampleJob
+	Job string `yaml:"job"`
+}
+
+// defaultConfig - значения по умолчанию, самый низкий приоритет
+func defaultConfig() *Config {
+	return &Config{
+		Db:       config.DefaultDb(),
+		RabbitMQ: config.DefaultRabbitMQ(),
+		Log:      config.DefaultLog(),
+		Health:   config.DefaultHealth(),
+		Shutdown: config.DefaultShutdown(),
+		RBAC:     config.DefaultRBAC(),
+	}
+}
+
+func (c *Config) GetCronSchedule() time.Duration {
+	return c.Cron.Schedule
+}
+
+func (c *Config) GetCronTimeout() time.Duration {
+	return c.Cron.Timeout
+}
+
+func (c *Config) GetCronJob() string {
+	return c.Cron.Job
+}
+
+// Validate проверяет обязательные поля и форматы значений.
+// Блок rabbitmq для крон скриптов необязателен и проверяется, только если задан.
+// Возвращает *config.ValidationError со списком всех нарушений или nil.
+func (c *Config) Validate() error {
+	v := &config.Validator{}
+	c.Db.Validate(v)
+	if c.RabbitMQ.URL != "" {
+		c.RabbitMQ.Validate(v)
+	}
+	c.Log.Validate(v)
+	c.Health.Validate(v)
+	c.Shutdown.Validate(v)
+	c.RBAC.Validate(v)
+	if c.Cron.Schedule < 0 {
+		v.Add("cron.schedule", "must be >= 0, got %s", c.Cron.Schedule)
+	}
+	if c.Cron.Timeout < 0 {
+		v.Add("cron.timeout", "must be >= 0, got %s", c.Cron.Timeout)
+	}
+	return v.Err()
+}
+
+// Print выводит итоговую конфигурацию с замаскированными секретами
+func (c *Config) Print(w io.Writer) error {
+	return config.Print(w, c)
+}
+
+// Load собирает конфигурацию крона, порядок источников описан в config.Load
+func Load(args []string, environ []string) (*Config, error) {
+	cf := defaultConfig()
+	if err := config.Load(cf, args, environ); err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// NewConfig собирает конфигурацию из аргументов командной строки и окружения
+// и проверяет её
+func NewConfig() (interfaces.ConfigCron, error) {
+	cf, err := Load(os.Args[1:], os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cf, nil
+}
+
+// NewReloader создаёт config.Reloader, перечитывающий конфигурацию крона
+func NewReloader(cf interfaces.ConfigCron) *config.Reloader {
+	current, ok := cf.(*Config)
+	if !ok {
+		return config.NewReloader(nil, nil)
+	}
+	args := append([]string(nil), os.Args[1:]...)
+	return config.NewReloader(current, func() (config.Reloadable, error) {
+		return Load(args, os.Environ())
+	})
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix - префикс переменных окружения, переопределяющих конфигурацию.
+// Ключ db.dsn соответствует переменной GOBASE_DB_DSN.
+const EnvPrefix = "GOBASE_"
+
+// Служебные флаги командной строки, не являющиеся ключами конфигурации
+const (
+	flagConfig      = "config"
+	flagPrintConfig = "print-config"
+	flagPrintGraph  = "print-graph"
+)
+
+// EnvConfigPath - переменная окружения с путём к YAML файлу конфигурации
+const EnvConfigPath = EnvPrefix + "CONFIG"
+
+// Load заполняет dst (указатель на структуру конфигурации с уже выставленными
+// значениями по умолчанию) из источников в порядке возрастания приоритета:
+//
+//  1. значения по умолчанию (заданы в dst до вызова)
+//  2. YAML файл (первый позиционный аргумент, --config=<path> или GOBASE_CONFIG)
+//  3. переменные окружения GOBASE_* (GOBASE_DB_DSN, GOBASE_APP_RUN_ADDR, ...)
+//  4. флаги командной строки вида --db.dsn=<value>; флаг без значения
+//     допустим только для bool полей (true), для остальных это ошибка
+//
+// Каждый следующий источник переопределяет значения предыдущего.
+// После слияния ключи с суффиксом _file (db.dsn_file) заменяются содержимым файла.
+func Load(dst interface{}, args []string, environ []string) error {
+	env := envMap(environ)
+
+	flags, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	path, err := ConfigPath(args, environ)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		if err := loadFile(dst, path, env); err != nil {
+			return err
+		}
+	}
+
+	fields := configFields(dst)
+
+	for _, f := range fields {
+		if v, ok := env[f.envName()]; ok {
+			if err := f.set(v); err != nil {
+				return fmt.Errorf("env %s: %w", f.envName(), err)
+			}
+		}
+	}
+
+	known := make(map[string]configField, len(fields))
+	for _, f := range fields {
+		known[f.key] = f
+	}
+	for key, v := range flags.values {
+		if key == flagConfig || key == flagPrintConfig || key == flagPrintGraph {
+			continue
+		}
+		f, ok := known[key]
+		if !ok {
+			return fmt.Errorf("unknown flag --%s", key)
+		}
+		if flags.bare[key] && f.value.Kind() != reflect.Bool {
+			return fmt.Errorf("flag --%s requires a value: --%s=<value>", key, key)
+		}
+		if err := f.set(v); err != nil {
+			return fmt.Errorf("flag --%s: %w", key, err)
+		}
+	}
+
+	return resolveFileRefs(fields)
+}
+
+// ConfigPath возвращает путь к YAML файлу по тем же правилам, что и Load
+func ConfigPath(args []string, environ []string) (string, error) {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return "", err
+	}
+	path := envMap(environ)[EnvConfigPath]
+	if len(flags.positional) > 0 {
+		path = flags.positional[0]
+	}
+	if p, ok := flags.values[flagConfig]; ok {
+		if flags.bare[flagConfig] {
+			return "", fmt.Errorf("flag --%s requires a value: --%s=<path>", flagConfig, flagConfig)
+		}
+		path = p
+	}
+	return path, nil
+}
+
+// PrintConfigRequested сообщает, передан ли флаг --print-config
+func PrintConfigRequested(args []string) bool {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return false
+	}
+	_, ok := flags.values[flagPrintConfig]
+	return ok
+}
+
+// PrintGraphRequested возвращает формат из флага --print-graph[=dot|json].
+// Флаг без значения означает формат dot.
+func PrintGraphRequested(args []string) (string, bool) {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return "", false
+	}
+	format, ok := flags.values[flagPrintGraph]
+	if format == "true" {
+		format = "dot"
+	}
+	return format, ok
+}
+
+// loadFile читает YAML файл поверх текущих значений dst, подставляя ${VAR} из env
+func loadFile(dst interface{}, path string, env map[string]string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// Пустой файл - остаются значения по умолчанию
+		return nil
+	}
+
+	if err := interpolate(&doc, env); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	if err := doc.Decode(dst); err != nil {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// cmdFlags - разобранные аргументы командной строки
+type cmdFlags struct {
+	values map[string]string
+	// bare - флаги, переданные без "=" (их значение "true")
+	bare       map[string]bool
+	positional []string
+}
+
+// parseFlags разбирает аргументы вида --key=value и -key=value. Значение
+// принимается только через "=": флаг без него (--print-config, --print-graph)
+// получает значение "true", а следующий аргумент остаётся позиционным, иначе
+// "--print-config config.yaml" принял бы путь к конфигурации за значение флага.
+// Флаги, которым нужно значение (--config, --db.dsn), без "=" отклоняет Load.
+func parseFlags(args []string) (*cmdFlags, error) {
+	flags := &cmdFlags{values: make(map[string]string), bare: make(map[string]bool)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			flags.positional = append(flags.positional, arg)
+			continue
+		}
+		if arg == "--" {
+			flags.positional = append(flags.positional, args[i+1:]...)
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if name == "" {
+			return nil, fmt.Errorf("bad flag syntax: %s", arg)
+		}
+
+		if key, value, ok := strings.Cut(name, "="); ok {
+			flags.values[key] = value
+			delete(flags.bare, key)
+			continue
+		}
+		flags.values[name] = "true"
+		flags.bare[name] = true
+	}
+
+	return flags, nil
+}
+
+func envMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// configField - лист структуры конфигурации, адресуемый ключом вида db.dsn
+type configField struct {
+	key     string
+	value   reflect.Value
+	restart bool
+}
+
+func (f configField) envName() string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(f.key))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (f configField) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.CanFloat():
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// configFields обходит структуру по yaml тегам и возвращает все листовые поля
+func configFields(dst interface{}) []configField {
+	var fields []configField
+	walkFields(reflect.ValueOf(dst).Elem(), "", &fields)
+	return fields
+}
+
+func walkFields(v reflect.Value, prefix string, fields *[]configField) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			walkFields(fv, key, fields)
+			continue
+		}
+
+		*fields = append(*fields, configField{
+			key:     key,
+			value:   fv,
+			restart: sf.Tag.Get("reload") == "restart",
+		})
+	}
+}
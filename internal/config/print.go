@@ -0,0 +1,18 @@
+package config
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Print выводит итоговую (после слияния всех источников) конфигурацию
+// в формате YAML. Значения типа Secret маскируются.
+func Print(w io.Writer, cf interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cf); err != nil {
+		return err
+	}
+	return enc.Close()
+}
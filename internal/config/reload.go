@@ -0,0 +1,189 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// ErrReloadUnsupported возвращается, если конфигурация создана не через Load
+// (например, мок в тестах) и перечитать её невозможно
+var ErrReloadUnsupported = errors.New("config reload is not supported for this config source")
+
+// Reloadable - конфигурация бинарника, которую можно перечитать на лету.
+// Реализация должна быть указателем на структуру.
+type Reloadable interface {
+	interfaces.ConfigCommon
+	Validate() error
+}
+
+// Subscriber получает новый снимок конфигурации после успешной перезагрузки.
+// Для полей конкретного бинарника снимок приводится к его интерфейсу
+// (например, interfaces.ConfigServer).
+type Subscriber func(cf interfaces.ConfigCommon)
+
+// ReloadResult - итог перезагрузки
+type ReloadResult struct {
+	// Changed - ключи, новые значения которых применены
+	Changed []string
+	// RequiresRestart - ключи с тегом reload:"restart", изменения которых
+	// проигнорированы до перезапуска процесса
+	RequiresRestart []string
+}
+
+// Reloader перечитывает конфигурацию по SIGHUP (и, опционально, при изменении файла),
+// валидирует её и рассылает новый снимок подписчикам.
+// Поля с тегом reload:"restart" (run_addr, dsn, ...) не меняются на лету.
+type Reloader struct {
+	mu          sync.RWMutex
+	current     Reloadable
+	load        func() (Reloadable, error)
+	subscribers []Subscriber
+}
+
+// NewReloader создаёт Reloader. current - текущая конфигурация,
+// load - функция, заново собирающая её из всех источников.
+// При current == nil перезагрузка не поддерживается.
+func NewReloader(current Reloadable, load func() (Reloadable, error)) *Reloader {
+	return &Reloader{
+		current: current,
+		load:    load,
+	}
+}
+
+// Current возвращает актуальный снимок конфигурации
+func (r *Reloader) Current() interfaces.ConfigCommon {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Subscribe регистрирует подписчика на изменения конфигурации
+func (r *Reloader) Subscribe(fn Subscriber) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Reload перечитывает и валидирует конфигурацию. При ошибке текущий снимок
+// остаётся без изменений. Подписчики вызываются, только если что-то изменилось.
+func (r *Reloader) Reload() (*ReloadResult, error) {
+	r.mu.RLock()
+	supported := r.current != nil
+	r.mu.RUnlock()
+	if !supported {
+		return nil, ErrReloadUnsupported
+	}
+
+	next, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	result := applyReloadable(r.current, next)
+	if len(result.Changed) > 0 {
+		r.current = next
+	}
+	subscribers := append([]Subscriber(nil), r.subscribers...)
+	r.mu.Unlock()
+
+	if len(result.Changed) > 0 {
+		for _, fn := range subscribers {
+			fn(next)
+		}
+	}
+
+	return result, nil
+}
+
+// Watch перезагружает конфигурацию по SIGHUP, а при interval > 0 дополнительно
+// опрашивает время изменения YAML файла. Блокируется до отмены ctx.
+func (r *Reloader) Watch(ctx context.Context, path string, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	var modTime time.Time
+	if interval > 0 && path != "" {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+		modTime = fileModTime(path)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("Received SIGHUP, reloading config")
+			r.reloadAndLog()
+		case <-tick:
+			if mt := fileModTime(path); !mt.Equal(modTime) {
+				modTime = mt
+				log.Printf("Config file %s changed, reloading config", path)
+				r.reloadAndLog()
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndLog() {
+	result, err := r.Reload()
+	if err != nil {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		return
+	}
+	for _, key := range result.RequiresRestart {
+		log.Printf("Config key %s changed but requires restart", key)
+	}
+	if len(result.Changed) == 0 {
+		log.Println("Config reloaded: no applicable changes")
+		return
+	}
+	log.Printf("Config reloaded, changed keys: %v", result.Changed)
+}
+
+// applyReloadable сравнивает снимки и возвращает в next текущие значения
+// полей, которые нельзя менять без перезапуска
+func applyReloadable(prev, next Reloadable) *ReloadResult {
+	result := &ReloadResult{}
+	prevFields := configFields(prev)
+	nextFields := configFields(next)
+
+	for i, pf := range prevFields {
+		nf := nextFields[i]
+		if reflect.DeepEqual(pf.value.Interface(), nf.value.Interface()) {
+			continue
+		}
+		if pf.restart {
+			result.RequiresRestart = append(result.RequiresRestart, pf.key)
+			nf.value.Set(pf.value)
+			continue
+		}
+		result.Changed = append(result.Changed, pf.key)
+	}
+
+	return result
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// reloadConfig - конфигурация бинарника с общими блоками и полем, меняющимся только после перезапуска
+type reloadConfig struct {
+	Db       `yaml:"db"`
+	RabbitMQ `yaml:"rabbitmq"`
+	Log      `yaml:"log"`
+	Health   `yaml:"health"`
+	Shutdown `yaml:"shutdown"`
+	Modules  `yaml:"modules"`
+	RBAC     `yaml:"rbac"`
+	App      struct {
+		RunAddr string `yaml:"run_addr" reload:"restart"`
+	} `yaml:"app"`
+}
+
+func (c *reloadConfig) Validate() error {
+	v := &Validator{}
+	c.Log.Validate(v)
+	return v.Err()
+}
+
+func newReloadConfig(level, runAddr string) *reloadConfig {
+	c := &reloadConfig{Log: Log{Level: level}}
+	c.App.RunAddr = runAddr
+	return c
+}
+
+// newTestReloader возвращает Reloader, который при каждой перезагрузке читает *next
+func newTestReloader(current *reloadConfig, next **reloadConfig) (*Reloader, *[]interfaces.ConfigCommon) {
+	r := NewReloader(current, func() (Reloadable, error) {
+		c := **next
+		return &c, nil
+	})
+	var notified []interfaces.ConfigCommon
+	r.Subscribe(func(cf interfaces.ConfigCommon) {
+		notified = append(notified, cf)
+	})
+	return r, &notified
+}
+
+func TestReloaderAppliesChanges(t *testing.T) {
+	next := newReloadConfig("debug", ":8080")
+	r, notified := newTestReloader(newReloadConfig("info", ":8080"), &next)
+
+	result, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"log.level"}) || len(result.RequiresRestart) != 0 {
+		t.Fatalf("result = %+v, want log.level changed", result)
+	}
+	if len(*notified) != 1 || (*notified)[0].GetLogLevel() != "debug" {
+		t.Fatalf("subscribers got %v, want one debug snapshot", *notified)
+	}
+	if r.Current().GetLogLevel() != "debug" {
+		t.Fatalf("current level = %s, want debug", r.Current().GetLogLevel())
+	}
+
+	// Тот же снимок ещё раз - подписчики не вызываются
+	if result, err := r.Reload(); err != nil || len(result.Changed) != 0 {
+		t.Fatalf("repeated reload = %+v, %v", result, err)
+	}
+	if len(*notified) != 1 {
+		t.Fatalf("subscribers called %d times, want 1", len(*notified))
+	}
+}
+
+func TestReloaderRestartOnlyFields(t *testing.T) {
+	next := newReloadConfig("info", ":9090")
+	r, notified := newTestReloader(newReloadConfig("info", ":8080"), &next)
+
+	result, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changed) != 0 || !reflect.DeepEqual(result.RequiresRestart, []string{"app.run_addr"}) {
+		t.Fatalf("result = %+v, want app.run_addr requiring restart", result)
+	}
+	if len(*notified) != 0 {
+		t.Fatal("subscribers called without applicable changes")
+	}
+	if addr := r.Current().(*reloadConfig).App.RunAddr; addr != ":8080" {
+		t.Fatalf("run_addr = %s, want :8080 until restart", addr)
+	}
+
+	// Вместе с применимыми изменениями поле перезапуска остаётся прежним
+	next = newReloadConfig("warn", ":9090")
+	result, err = r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"log.level"}) || !reflect.DeepEqual(result.RequiresRestart, []string{"app.run_addr"}) {
+		t.Fatalf("result = %+v", result)
+	}
+	current := r.Current().(*reloadConfig)
+	if current.App.RunAddr != ":8080" || current.Log.Level != "warn" {
+		t.Fatalf("current = %+v, want warn on :8080", current)
+	}
+	if len(*notified) != 1 || (*notified)[0].(*reloadConfig).App.RunAddr != ":8080" {
+		t.Fatalf("subscribers got %v, want snapshot with old run_addr", *notified)
+	}
+}
+
+func TestReloaderKeepsSnapshotOnError(t *testing.T) {
+	current := newReloadConfig("info", ":8080")
+	next := newReloadConfig("verbose", ":8080")
+	r, notified := newTestReloader(current, &next)
+
+	var validationErr *ValidationError
+	if _, err := r.Reload(); !errors.As(err, &validationErr) {
+		t.Fatalf("Reload = %v, want validation error", err)
+	}
+	if r.Current() != current || len(*notified) != 0 {
+		t.Fatal("invalid config replaced the current snapshot")
+	}
+
+	loadErr := errors.New("read config file: permission denied")
+	failing := NewReloader(current, func() (Reloadable, error) { return nil, loadErr })
+	if _, err := failing.Reload(); !errors.Is(err, loadErr) {
+		t.Fatalf("Reload = %v, want load error", err)
+	}
+	if failing.Current() != current {
+		t.Fatal("failed load replaced the current snapshot")
+	}
+
+	if _, err := NewReloader(nil, nil).Reload(); !errors.Is(err, ErrReloadUnsupported) {
+		t.Fatalf("Reload without config = %v, want ErrReloadUnsupported", err)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type interpolateConfig struct {
+	Db struct {
+		DSN  Secret `yaml:"dsn"`
+		Port int    `yaml:"port"`
+	} `yaml:"db"`
+	Key     string        `yaml:"key"`
+	Timeout time.Duration `yaml:"timeout"`
+	Tags    []string      `yaml:"tags"`
+	Label   string        `yaml:"label"`
+}
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileInterpolatesScalars(t *testing.T) {
+	path := writeConfig(t, `
+# пример в комментарии не требует переменной: ${NOT_SET}
+db:
+  dsn: ${DB_DSN}
+  port: ${DB_PORT}
+key: "${PEM_KEY}"
+timeout: ${TIMEOUT:-5s}
+tags: ["${TAG}", static]
+label: "$${literal}"
+`)
+	env := map[string]string{
+		"DB_DSN":  "postgres://u:p#ss: word@host/db\nextra: injected",
+		"DB_PORT": "5433",
+		"PEM_KEY": "-----BEGIN KEY-----\n*abc&def\n-----END KEY-----",
+		"TAG":     "a: b",
+	}
+
+	var cfg interpolateConfig
+	if err := loadFile(&cfg, path, env); err != nil {
+		t.Fatal(err)
+	}
+	if string(cfg.Db.DSN) != env["DB_DSN"] {
+		t.Errorf("dsn = %q", string(cfg.Db.DSN))
+	}
+	if cfg.Db.Port != 5433 {
+		t.Errorf("port = %d, want 5433", cfg.Db.Port)
+	}
+	if cfg.Key != env["PEM_KEY"] {
+		t.Errorf("key = %q", cfg.Key)
+	}
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("timeout = %s, want 5s", cfg.Timeout)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a: b" {
+		t.Errorf("tags = %q", cfg.Tags)
+	}
+	if cfg.Label != "${literal}" {
+		t.Errorf("label = %q", cfg.Label)
+	}
+}
+
+func TestLoadFileUndefinedVariables(t *testing.T) {
+	path := writeConfig(t, "db:\n  dsn: ${B}\nkey: ${A}\n")
+
+	var cfg interpolateConfig
+	err := loadFile(&cfg, path, nil)
+	if err == nil || !strings.Contains(err.Error(), "undefined environment variables: A, B") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
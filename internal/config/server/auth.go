@@ -0,0 +1,111 @@
+package config
+
+import (
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/config"
+)
+
+// Auth - аутентификация запросов (internal/auth)
+type Auth struct {
+	JWT    JWT    `yaml:"jwt"`
+	APIKey APIKey `yaml:"api_key"`
+}
+
+// JWT - проверка токенов схемы Bearer. Ключи: secret для HS256,
+// PEM файлы public_keys и/или локальный JWKS для RS256 и EdDSA
+type JWT struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// Algorithms - допустимые алгоритмы: HS256, RS256, EdDSA
+	Algorithms []string `yaml:"algorithms" reload:"restart"`
+	// Secret - ключ HS256; SecretFile - путь к файлу с ключом (Docker/Kubernetes secrets)
+	Secret     config.Secret `yaml:"secret" reload:"restart"`
+	SecretFile string        `yaml:"secret_file" reload:"restart"`
+	// PublicKeys - PEM файлы открытых ключей: "path" или "kid=path"
+	PublicKeys []string `yaml:"public_keys" reload:"restart"`
+	JWKSFile   string   `yaml:"jwks_file" reload:"restart"`
+	// Issuer и Audience - ожидаемые iss и aud, пустые - не проверяются
+	Issuer   string `yaml:"issuer" reload:"restart"`
+	Audience string `yaml:"audience" reload:"restart"`
+	// Leeway - допуск расхождения часов для exp, nbf и iat
+	Leeway time.Duration `yaml:"leeway" reload:"restart"`
+}
+
+// APIKey - проверка ключей схемы ApiKey модулем apikeys. Ключи выпускает
+// API /api/v1/admin/api-keys.
+type APIKey struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// RotationGracePeriod - сколько прежний секрет действует после ротации,
+	// чтобы клиенты успели переключиться. 0 - перестаёт действовать сразу
+	RotationGracePeriod time.Duration `yaml:"rotation_grace_period" reload:"restart"`
+}
+
+// defaultAuth - значения по умолчанию для блока auth
+func defaultAuth() Auth {
+	return Auth{
+		JWT: JWT{
+			Algorithms: []string{auth.AlgRS256},
+			Leeway:     30 * time.Second,
+		},
+	}
+}
+
+func (a *Auth) GetJWTEnabled() bool {
+	return a.JWT.Enabled
+}
+
+func (a *Auth) GetAPIKeyEnabled() bool {
+	return a.APIKey.Enabled
+}
+
+func (a *Auth) GetAPIKeyRotationGracePeriod() time.Duration {
+	return a.APIKey.RotationGracePeriod
+}
+
+func (a *Auth) GetJWTConfig() auth.JWTConfig {
+	return auth.JWTConfig{
+		Algorithms:     a.JWT.Algorithms,
+		Secret:         []byte(a.JWT.Secret),
+		PublicKeyFiles: a.JWT.PublicKeys,
+		JWKSFile:       a.JWT.JWKSFile,
+		Issuer:         a.JWT.Issuer,
+		Audience:       a.JWT.Audience,
+		Leeway:         a.JWT.Leeway,
+	}
+}
+
+// Validate проверяет блок auth. Ключи из файлов загружаются при сборке
+// роутера: ошибка чтения или формата ключа останавливает старт сервера.
+func (a *Auth) Validate(v *config.Validator) {
+	if a.APIKey.RotationGracePeriod < 0 {
+		v.Add("auth.api_key.rotation_grace_period", "must be >= 0, got %s", a.APIKey.RotationGracePeriod)
+	}
+	j := a.JWT
+	if !j.Enabled {
+		return
+	}
+	if len(j.Algorithms) == 0 {
+		v.Add("auth.jwt.algorithms", "at least one algorithm is required")
+	}
+	for _, alg := range j.Algorithms {
+		switch alg {
+		case auth.AlgHS256:
+			if j.Secret == "" && j.JWKSFile == "" {
+				v.Add("auth.jwt.secret", "is required for %s", alg)
+			}
+		case auth.AlgRS256, auth.AlgEdDSA:
+			if len(j.PublicKeys) == 0 && j.JWKSFile == "" {
+				v.Add("auth.jwt.public_keys", "public_keys or jwks_file is required for %s", alg)
+			}
+		default:
+			v.Add("auth.jwt.algorithms", "must be %s, %s or %s, got %q", auth.AlgHS256, auth.AlgRS256, auth.AlgEdDSA, alg)
+		}
+	}
+	if j.Secret != "" && len(j.Secret) < 32 {
+		v.Add("auth.jwt.secret", "must be at least 32 bytes")
+	}
+	if j.Leeway < 0 {
+		v.Add("auth.jwt.leeway", "must be >= 0, got %s", j.Leeway)
+	}
+}
@@ -1,73 +1,144 @@
 package config
 
 import (
+	"github.com/SmirnovND/gobase/internal/config"
 	"github.com/SmirnovND/gobase/internal/interfaces"
-	"gopkg.in/yaml.v3"
-	"log"
+	"io"
 	"os"
+	"time"
 )
 
+// Config - конфигурация HTTP сервера (cmd/server).
+// Общие блоки db, rabbitmq и log описаны в internal/config.
 type Config struct {
-	Db       `yaml:"db"`
-	App      `yaml:"app"`
-	RabbitMQ `yaml:"rabbitmq"`
+	config.Db       `yaml:"db"`
+	App             `yaml:"app"`
+	config.RabbitMQ `yaml:"rabbitmq"`
+	config.Log      `yaml:"log"`
+	config.Health   `yaml:"health"`
+	config.Shutdown `yaml:"shutdown"`
+	config.Modules  `yaml:"modules"`
+	config.RBAC     `yaml:"rbac"`
+	Middleware      `yaml:"middleware"`
+	Auth            `yaml:"auth"`
 }
 
-type Db struct {
-	Dsn          string `yaml:"dsn"`
-	MaxOpenConns int    `yaml:"max_open_conns"`
-	MaxIdleConns int    `yaml:"max_idle_conns"`
-}
+// Окружения app.env
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
 
 type App struct {
-	RunAddr string `yaml:"run_addr"`
+	RunAddr string `yaml:"run_addr" reload:"restart"`
+	// Env - окружение: в development JSON ответы форматируются с отступами
+	Env string `yaml:"env" reload:"restart"`
+	// ConfigWatchInterval - период опроса YAML файла на изменения, 0 - только SIGHUP
+	ConfigWatchInterval time.Duration `yaml:"config_watch_interval" reload:"restart"`
 }
 
-type RabbitMQ struct {
-	URL string `yaml:"url"`
+// defaultConfig - значения по умолчанию, самый низкий приоритет
+func defaultConfig() *Config {
+	return &Config{
+		Db: config.DefaultDb(),
+		App: App{
+			RunAddr: "localhost:8080",
+			Env:     EnvProduction,
+		},
+		RabbitMQ:   serverRabbitMQ(),
+		Log:        config.DefaultLog(),
+		Health:     config.DefaultHealth(),
+		Shutdown:   config.DefaultShutdown(),
+		RBAC:       config.DefaultRBAC(),
+		Middleware: defaultMiddleware(),
+		Auth:       defaultAuth(),
+	}
 }
 
-func (c *Config) GetDBDsn() string {
-	return c.Db.Dsn
+// serverRabbitMQ - RabbitMQ для HTTP API необязателен: без него сервер стартует
+// в деградированном режиме, а эндпоинты, не использующие очереди, продолжают работать
+func serverRabbitMQ() config.RabbitMQ {
+	r := config.DefaultRabbitMQ()
+	r.Required = false
+	return r
 }
 
-func (c *Config) GetDBMaxOpenConns() int {
-	return c.Db.MaxOpenConns
+func (c *Config) GetRunAddr() string {
+	return c.App.RunAddr
 }
 
-func (c *Config) GetDBMaxIdleConns() int {
-	return c.Db.MaxIdleConns
+func (c *Config) GetAppEnv() string {
+	return c.App.Env
 }
 
-func (c *Config) GetRunAddr() string {
-	return c.App.RunAddr
+func (c *Config) IsDevelopment() bool {
+	return c.App.Env == EnvDevelopment
 }
 
-func (c *Config) GetRabbitMQURL() string {
-	return c.RabbitMQ.URL
+func (c *Config) GetConfigWatchInterval() time.Duration {
+	return c.App.ConfigWatchInterval
 }
 
-func NewConfig() interfaces.ConfigServer {
-	if len(os.Args) < 2 {
-		log.Fatal("Config file path not provided. Usage: ./server <config.yaml>")
+// Validate проверяет обязательные поля и форматы значений.
+// Возвращает *config.ValidationError со списком всех нарушений или nil.
+func (c *Config) Validate() error {
+	v := &config.Validator{}
+	c.Db.Validate(v)
+	config.ValidateAddr(v, "app.run_addr", c.App.RunAddr)
+	if c.App.Env != EnvDevelopment && c.App.Env != EnvProduction {
+		v.Add("app.env", "must be one of %s, %s, got %q", EnvDevelopment, EnvProduction, c.App.Env)
+	}
+	if c.App.ConfigWatchInterval < 0 {
+		v.Add("app.config_watch_interval", "must be >= 0, got %s", c.App.ConfigWatchInterval)
 	}
+	c.RabbitMQ.Validate(v)
+	c.Log.Validate(v)
+	c.Health.Validate(v)
+	c.Shutdown.Validate(v)
+	c.RBAC.Validate(v)
+	c.Middleware.Validate(v)
+	c.Auth.Validate(v)
+	return v.Err()
+}
 
-	cPath := os.Args[1]
-	cf := &Config{}
-	cf.LoadConfig(cPath)
+// Print выводит итоговую конфигурацию с замаскированными секретами
+func (c *Config) Print(w io.Writer) error {
+	return config.Print(w, c)
+}
 
-	return cf
+// Load собирает конфигурацию сервера, порядок источников описан в config.Load
+func Load(args []string, environ []string) (*Config, error) {
+	cf := defaultConfig()
+	if err := config.Load(cf, args, environ); err != nil {
+		return nil, err
+	}
+	return cf, nil
 }
 
-func (c *Config) LoadConfig(patch string) {
-	file, err := os.Open(patch)
+// NewConfig собирает конфигурацию из аргументов командной строки и окружения
+// и проверяет её. При ошибке валидации возвращается *config.ValidationError
+// со всеми нарушениями.
+func NewConfig() (interfaces.ConfigServer, error) {
+	cf, err := Load(os.Args[1:], os.Environ())
 	if err != nil {
-		log.Fatal("ReadConfigFile: ", err)
+		return nil, err
 	}
 
-	decoder := yaml.NewDecoder(file)
-	err = decoder.Decode(&c)
-	if err != nil {
-		log.Fatal("DecodeConfigFile: ", err)
+	if err := cf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cf, nil
+}
+
+// NewReloader создаёт config.Reloader, перечитывающий конфигурацию сервера
+func NewReloader(cf interfaces.ConfigServer) *config.Reloader {
+	current, ok := cf.(*Config)
+	if !ok {
+		return config.NewReloader(nil, nil)
 	}
+	args := append([]string(nil), os.Args[1:]...)
+	return config.NewReloader(current, func() (config.Reloadable, error) {
+		return Load(args, os.Environ())
+	})
 }
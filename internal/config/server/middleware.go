@@ -0,0 +1,384 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/config"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/ratelimit"
+)
+
+// Middleware - HTTP middleware сервера. Порядок применения задан
+// в internal/middleware.Stack, здесь - только включение и настройки.
+type Middleware struct {
+	RequestID RequestID `yaml:"request_id"`
+	Recovery  Recovery  `yaml:"recovery"`
+	AccessLog AccessLog `yaml:"access_log"`
+	RealIP    RealIP    `yaml:"real_ip"`
+	Timeout   Timeout   `yaml:"timeout"`
+	BodyLimit BodyLimit `yaml:"body_limit"`
+	CORS      CORS      `yaml:"cors"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+}
+
+type RequestID struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// Header - заголовок запроса и ответа с идентификатором
+	Header string `yaml:"header" reload:"restart"`
+}
+
+type Recovery struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+}
+
+type AccessLog struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// SkipPaths - пути без записи в журнал (например, часто опрашиваемые пробы)
+	SkipPaths []string `yaml:"skip_paths" reload:"restart"`
+}
+
+type RealIP struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// TrustedProxies - адреса и подсети (CIDR) прокси, которым доверяют
+	// X-Forwarded-For и X-Real-IP. Пусто - заголовки игнорируются
+	TrustedProxies []string `yaml:"trusted_proxies" reload:"restart"`
+}
+
+type Timeout struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// Default - дедлайн обработки запроса
+	Default time.Duration `yaml:"default" reload:"restart"`
+	// Routes - дедлайны по префиксу пути вида "/api/v1/reports=2m", побеждает самый длинный префикс
+	Routes []string `yaml:"routes" reload:"restart"`
+}
+
+type BodyLimit struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// MaxBytes - максимальный размер тела запроса
+	MaxBytes int64 `yaml:"max_bytes" reload:"restart"`
+	// Routes - лимиты по префиксу пути вида "/api/v1/uploads=52428800"
+	Routes []string `yaml:"routes" reload:"restart"`
+}
+
+// CORS - глобальная политика CORS. Группы маршрутов со своей политикой
+// подключают middleware.CORS в RegisterRoutes
+type CORS struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// AllowedOrigins - "https://app.example.com", "https://*.example.com" или "*"
+	AllowedOrigins   []string      `yaml:"allowed_origins" reload:"restart"`
+	AllowedMethods   []string      `yaml:"allowed_methods" reload:"restart"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" reload:"restart"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" reload:"restart"`
+	AllowCredentials bool          `yaml:"allow_credentials" reload:"restart"`
+	MaxAge           time.Duration `yaml:"max_age" reload:"restart"`
+}
+
+// RateLimit - глобальный rate limiting. Лимиты, маршруты и источники ключа
+// применяются при перезагрузке конфигурации (SIGHUP), включение, алгоритм
+// и хранилище - после перезапуска
+type RateLimit struct {
+	Enabled bool `yaml:"enabled" reload:"restart"`
+	// Algorithm - token_bucket или sliding_window
+	Algorithm string `yaml:"algorithm" reload:"restart"`
+	// Store - memory (один экземпляр) или postgres (общие счётчики реплик)
+	Store string `yaml:"store" reload:"restart"`
+	// Keys - источники ключа по порядку: user, api_key, ip. Первый найденный в запросе
+	Keys     []string      `yaml:"keys"`
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+	// Burst - ёмкость token bucket, 0 - равна requests
+	Burst int `yaml:"burst"`
+	// Routes - лимиты по префиксу пути вида "/api/v1/auth=10/1m", у каждого свои счётчики
+	Routes []string `yaml:"routes"`
+	// Auth - лимит вида "100/1m" на запросы с заголовком Authorization по IP клиента,
+	// проверяется до аутентификации (подбор учётных данных). Пусто - без лимита
+	Auth string `yaml:"auth"`
+}
+
+// defaultMiddleware - значения по умолчанию для блока middleware
+func defaultMiddleware() Middleware {
+	return Middleware{
+		RequestID: RequestID{Enabled: true, Header: "X-Request-ID"},
+		Recovery:  Recovery{Enabled: true},
+		AccessLog: AccessLog{
+			Enabled:   true,
+			SkipPaths: []string{"/livez", "/readyz", "/startupz"},
+		},
+		RealIP:    RealIP{Enabled: true},
+		Timeout:   Timeout{Enabled: true, Default: 10 * time.Second},
+		BodyLimit: BodyLimit{Enabled: true, MaxBytes: 1 << 20},
+		CORS: CORS{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+			ExposedHeaders: []string{"X-Request-ID", "X-Total-Count", "Link", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"},
+			MaxAge:         10 * time.Minute,
+		},
+		RateLimit: RateLimit{
+			Algorithm: ratelimit.AlgorithmTokenBucket,
+			Store:     ratelimit.StoreMemory,
+			Keys:      []string{ratelimit.KeyUser, ratelimit.KeyAPIKey, ratelimit.KeyIP},
+			Requests:  100,
+			Window:    time.Minute,
+			Auth:      "100/1m",
+		},
+	}
+}
+
+func (m *Middleware) GetRequestIDEnabled() bool {
+	return m.RequestID.Enabled
+}
+
+func (m *Middleware) GetRequestIDHeader() string {
+	return m.RequestID.Header
+}
+
+func (m *Middleware) GetRecoveryEnabled() bool {
+	return m.Recovery.Enabled
+}
+
+func (m *Middleware) GetAccessLogEnabled() bool {
+	return m.AccessLog.Enabled
+}
+
+func (m *Middleware) GetAccessLogSkipPaths() []string {
+	return m.AccessLog.SkipPaths
+}
+
+func (m *Middleware) GetRealIPEnabled() bool {
+	return m.RealIP.Enabled
+}
+
+// GetRealIPTrustedProxies возвращает доверенные подсети; одиночный адрес - подсеть /32 или /128
+func (m *Middleware) GetRealIPTrustedProxies() []*net.IPNet {
+	nets, _ := parseTrustedProxies(m.RealIP.TrustedProxies)
+	return nets
+}
+
+func (m *Middleware) GetTimeoutEnabled() bool {
+	return m.Timeout.Enabled
+}
+
+func (m *Middleware) GetTimeoutDefault() time.Duration {
+	return m.Timeout.Default
+}
+
+// GetTimeoutRoutes возвращает дедлайны по префиксам пути
+func (m *Middleware) GetTimeoutRoutes() map[string]time.Duration {
+	routes, _ := parseRoutes(m.Timeout.Routes, time.ParseDuration)
+	return routes
+}
+
+func (m *Middleware) GetBodyLimitEnabled() bool {
+	return m.BodyLimit.Enabled
+}
+
+func (m *Middleware) GetBodyLimitMaxBytes() int64 {
+	return m.BodyLimit.MaxBytes
+}
+
+// GetBodyLimitRoutes возвращает лимиты размера тела по префиксам пути
+func (m *Middleware) GetBodyLimitRoutes() map[string]int64 {
+	routes, _ := parseRoutes(m.BodyLimit.Routes, parseBytes)
+	return routes
+}
+
+func (m *Middleware) GetCORSEnabled() bool {
+	return m.CORS.Enabled
+}
+
+func (m *Middleware) GetCORSPolicy() interfaces.CORSPolicy {
+	return interfaces.CORSPolicy{
+		AllowedOrigins:   m.CORS.AllowedOrigins,
+		AllowedMethods:   m.CORS.AllowedMethods,
+		AllowedHeaders:   m.CORS.AllowedHeaders,
+		ExposedHeaders:   m.CORS.ExposedHeaders,
+		AllowCredentials: m.CORS.AllowCredentials,
+		MaxAge:           m.CORS.MaxAge,
+	}
+}
+
+func (m *Middleware) GetRateLimitEnabled() bool {
+	return m.RateLimit.Enabled
+}
+
+func (m *Middleware) GetRateLimitAlgorithm() string {
+	return m.RateLimit.Algorithm
+}
+
+func (m *Middleware) GetRateLimitStore() string {
+	return m.RateLimit.Store
+}
+
+func (m *Middleware) GetRateLimitKeys() []string {
+	return m.RateLimit.Keys
+}
+
+func (m *Middleware) GetRateLimitDefault() ratelimit.Limit {
+	return ratelimit.Limit{Requests: m.RateLimit.Requests, Window: m.RateLimit.Window, Burst: m.RateLimit.Burst}
+}
+
+// GetRateLimitRoutes возвращает лимиты по префиксам пути
+func (m *Middleware) GetRateLimitRoutes() map[string]ratelimit.Limit {
+	routes, _ := parseRoutes(m.RateLimit.Routes, parseRateLimit)
+	return routes
+}
+
+// GetRateLimitAuth возвращает лимит запросов с Authorization до аутентификации;
+// нулевой Requests - лимит отключён
+func (m *Middleware) GetRateLimitAuth() ratelimit.Limit {
+	if m.RateLimit.Auth == "" {
+		return ratelimit.Limit{}
+	}
+	limit, _ := parseRateLimit(m.RateLimit.Auth)
+	return limit
+}
+
+// Validate проверяет блок middleware
+func (m *Middleware) Validate(v *config.Validator) {
+	if m.RequestID.Enabled && m.RequestID.Header == "" {
+		v.Add("middleware.request_id.header", "is required when request_id is enabled")
+	}
+	if _, err := parseTrustedProxies(m.RealIP.TrustedProxies); err != nil {
+		v.Add("middleware.real_ip.trusted_proxies", "%v", err)
+	}
+	if m.Timeout.Enabled && m.Timeout.Default <= 0 {
+		v.Add("middleware.timeout.default", "must be > 0, got %s", m.Timeout.Default)
+	}
+	if routes, err := parseRoutes(m.Timeout.Routes, time.ParseDuration); err != nil {
+		v.Add("middleware.timeout.routes", "%v", err)
+	} else {
+		for prefix, d := range routes {
+			if d <= 0 {
+				v.Add("middleware.timeout.routes", "%s: must be > 0, got %s", prefix, d)
+			}
+		}
+	}
+	if m.BodyLimit.Enabled && m.BodyLimit.MaxBytes <= 0 {
+		v.Add("middleware.body_limit.max_bytes", "must be > 0, got %d", m.BodyLimit.MaxBytes)
+	}
+	if _, err := parseRoutes(m.BodyLimit.Routes, parseBytes); err != nil {
+		v.Add("middleware.body_limit.routes", "%v", err)
+	}
+	m.CORS.validate(v)
+	m.RateLimit.validate(v)
+}
+
+func (l *RateLimit) validate(v *config.Validator) {
+	if _, err := ratelimit.AlgorithmByName(l.Algorithm); err != nil {
+		v.Add("middleware.rate_limit.algorithm", "must be %s or %s, got %q", ratelimit.AlgorithmTokenBucket, ratelimit.AlgorithmSlidingWindow, l.Algorithm)
+	}
+	if l.Store != ratelimit.StoreMemory && l.Store != ratelimit.StorePostgres {
+		v.Add("middleware.rate_limit.store", "must be %s or %s, got %q", ratelimit.StoreMemory, ratelimit.StorePostgres, l.Store)
+	}
+	if len(l.Keys) == 0 {
+		v.Add("middleware.rate_limit.keys", "at least one key is required")
+	}
+	if _, err := ratelimit.KeysByName(l.Keys); err != nil {
+		v.Add("middleware.rate_limit.keys", "%v", err)
+	}
+	if l.Requests <= 0 {
+		v.Add("middleware.rate_limit.requests", "must be > 0, got %d", l.Requests)
+	}
+	if l.Window <= 0 {
+		v.Add("middleware.rate_limit.window", "must be > 0, got %s", l.Window)
+	}
+	if l.Burst < 0 {
+		v.Add("middleware.rate_limit.burst", "must be >= 0, got %d", l.Burst)
+	}
+	if _, err := parseRoutes(l.Routes, parseRateLimit); err != nil {
+		v.Add("middleware.rate_limit.routes", "%v", err)
+	}
+	if l.Auth != "" {
+		if _, err := parseRateLimit(l.Auth); err != nil {
+			v.Add("middleware.rate_limit.auth", "%v", err)
+		}
+	}
+}
+
+func (c *CORS) validate(v *config.Validator) {
+	if c.Enabled && len(c.AllowedOrigins) == 0 {
+		v.Add("middleware.cors.allowed_origins", "is required when cors is enabled")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				v.Add("middleware.cors.allowed_origins", `"*" cannot be combined with allow_credentials`)
+			}
+			continue
+		}
+		if strings.Count(origin, "*") > 1 || !strings.Contains(origin, "://") {
+			v.Add("middleware.cors.allowed_origins", "%q: expected scheme://host[:port] with at most one *", origin)
+		}
+	}
+	if c.MaxAge < 0 {
+		v.Add("middleware.cors.max_age", "must be >= 0, got %s", c.MaxAge)
+	}
+}
+
+// parseTrustedProxies разбирает подсети CIDR и одиночные адреса
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q", entry)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// parseRoutes разбирает записи вида "<префикс пути>=<значение>"
+func parseRoutes[T any](entries []string, parse func(string) (T, error)) (map[string]T, error) {
+	routes := make(map[string]T, len(entries))
+	for _, entry := range entries {
+		prefix, raw, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("%q: expected /path/prefix=value", entry)
+		}
+		value, err := parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", entry, err)
+		}
+		routes[prefix] = value
+	}
+	return routes, nil
+}
+
+// parseRateLimit разбирает лимит вида "10/1m"
+func parseRateLimit(raw string) (ratelimit.Limit, error) {
+	requests, window, ok := strings.Cut(raw, "/")
+	n, err := strconv.Atoi(requests)
+	if !ok || err != nil || n <= 0 {
+		return ratelimit.Limit{}, fmt.Errorf("expected requests/window, e.g. 10/1m")
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return ratelimit.Limit{}, fmt.Errorf("window must be a positive duration")
+	}
+	return ratelimit.Limit{Requests: n, Window: d}, nil
+}
+
+func parseBytes(raw string) (int64, error) {
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("must be a positive number of bytes")
+	}
+	return n, nil
+}
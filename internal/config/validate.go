@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FieldError - нарушение правила валидации для конкретного ключа конфигурации
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationError содержит все найденные нарушения, а не только первое
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Validator накапливает нарушения, чтобы сообщить обо всех сразу
+type Validator struct {
+	errs []*FieldError
+}
+
+// Add добавляет нарушение для ключа
+func (v *Validator) Add(key, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает *ValidationError со всеми нарушениями или nil
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+// ValidateAddr проверяет формат host:port
+func ValidateAddr(v *Validator, key, addr string) {
+	if addr == "" {
+		v.Add(key, "is required")
+		return
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		v.Add(key, "must be in host:port format: %v", err)
+		return
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		v.Add(key, "invalid port %q", port)
+	}
+}
+
+// ValidateAMQPURL проверяет, что URL задан и имеет схему amqp или amqps.
+// Значение не попадает в текст ошибки, так как содержит учётные данные.
+func ValidateAMQPURL(v *Validator, key, raw string) {
+	if raw == "" {
+		v.Add(key, "is required")
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		v.Add(key, "is not a valid URL")
+		return
+	}
+	if u.Scheme != "amqp" && u.Scheme != "amqps" {
+		v.Add(key, "scheme must be amqp or amqps, got %q", u.Scheme)
+		return
+	}
+	if u.Host == "" {
+		v.Add(key, "host is required")
+	}
+}
@@ -0,0 +1,55 @@
+package container
+
+import (
+	consumerconfig "github.com/SmirnovND/gobase/internal/config/consumer"
+	cronconfig "github.com/SmirnovND/gobase/internal/config/cron"
+	serverconfig "github.com/SmirnovND/gobase/internal/config/server"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
+)
+
+// ConfigProvider регистрирует в контейнере конфигурацию конкретного бинарника.
+// Каждый провайдер обязан зарегистрировать interfaces.ConfigCommon (от него зависят
+// инфраструктурные провайдеры) и *config.Reloader.
+// dig.As здесь не подходит: для конструктора, уже возвращающего интерфейс,
+// он регистрирует только перечисленные типы и теряет исходный.
+type ConfigProvider func(c module.Provider) error
+
+// ServerConfig - конфигурация HTTP сервера: interfaces.ConfigServer
+func ServerConfig(c module.Provider) error {
+	if err := c.Provide(serverconfig.NewConfig); err != nil {
+		return err
+	}
+	if err := c.Provide(func(cf interfaces.ConfigServer) interfaces.ConfigCommon {
+		return cf
+	}); err != nil {
+		return err
+	}
+	return c.Provide(serverconfig.NewReloader)
+}
+
+// ConsumerConfig - конфигурация RabbitMQ consumer: interfaces.ConfigConsumer
+func ConsumerConfig(c module.Provider) error {
+	if err := c.Provide(consumerconfig.NewConfig); err != nil {
+		return err
+	}
+	if err := c.Provide(func(cf interfaces.ConfigConsumer) interfaces.ConfigCommon {
+		return cf
+	}); err != nil {
+		return err
+	}
+	return c.Provide(consumerconfig.NewReloader)
+}
+
+// CronConfig - конфигурация крон скриптов: interfaces.ConfigCron
+func CronConfig(c module.Provider) error {
+	if err := c.Provide(cronconfig.NewConfig); err != nil {
+		return err
+	}
+	if err := c.Provide(func(cf interfaces.ConfigCron) interfaces.ConfigCommon {
+		return cf
+	}); err != nil {
+		return err
+	}
+	return c.Provide(cronconfig.NewReloader)
+}
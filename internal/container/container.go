@@ -2,101 +2,247 @@ package container
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/SmirnovND/gobase/internal/adapter"
-	config "github.com/SmirnovND/gobase/internal/config/server"
-	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/config"
+	"github.com/SmirnovND/gobase/internal/infra"
 	"github.com/SmirnovND/gobase/internal/interfaces"
-	"github.com/SmirnovND/gobase/internal/repositories"
-	"github.com/SmirnovND/gobase/internal/services"
+	"github.com/SmirnovND/gobase/internal/lifecycle"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/modules"
 	"github.com/SmirnovND/toolbox/pkg/db"
 	"github.com/SmirnovND/toolbox/pkg/http"
-	"github.com/SmirnovND/toolbox/pkg/rabbitmq"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"go.uber.org/dig"
 	"go.uber.org/zap"
-	"log"
+	"time"
 )
 
+// defaultStopTimeout - дедлайн остановки для Close (defer без контекста)
+const defaultStopTimeout = 30 * time.Second
+
 // Container - структура контейнера, обертывающая dig-контейнер
 type Container struct {
 	container *dig.Container
-	closers   []interfaces.Closer
-	loggers   []interfaces.LoggerCloser
-}
-
-func NewContainer() *Container {
-	c := &Container{container: dig.New()}
-	c.provideDependencies()
-	c.provideRepo()
-	c.provideService()
-	c.provideUsecase()
-	c.provideController()
-	return c
-}
-
-// provideDependencies - функция, регистрирующая зависимости
-func (c *Container) provideDependencies() {
-	// Регистрируем конфигурацию
-	c.container.Provide(config.NewConfig)
-	c.container.Provide(func(configServer interfaces.ConfigServer) *sqlx.DB {
-		return db.NewDB(configServer.GetDBDsn())
-	})
-	c.container.Provide(db.NewTransactionManager)
-	c.container.Provide(http.NewAPIClient)
-	c.container.Provide(func() *zap.Logger {
-		logger, _ := zap.NewProduction()
-		c.loggers = append(c.loggers, logger)
-		return logger
+	// registry - все конструкторы контейнера (для Validate и PrintGraph)
+	registry  *registry
+	lifecycle *lifecycle.Manager
+	// modules - включённые модули фич
+	modules []module.Module
+}
+
+// NewContainer регистрирует зависимости и сразу разрешает конфигурацию,
+// чтобы ошибки загрузки и валидации проявлялись при старте, а не при первом Invoke.
+// configProvider - конфигурация бинарника: ServerConfig, ConsumerConfig или CronConfig.
+func NewContainer(configProvider ConfigProvider) (*Container, error) {
+	return newContainer(configProvider, nil)
+}
+
+// newContainer собирает контейнер; overrides регистрируются раньше остальных
+// провайдеров и заменяют конструкторы тех же типов (см. NewTestContainer)
+func newContainer(configProvider ConfigProvider, overrides []override) (*Container, error) {
+	c := &Container{container: dig.New(), lifecycle: lifecycle.NewManager()}
+	c.registry = &registry{container: c.container}
+	for _, o := range overrides {
+		if err := c.registry.override(o.ctor, o.opts...); err != nil {
+			return nil, fmt.Errorf("override: %w", err)
+		}
+	}
+	if err := configProvider(c.registry); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	// Ошибки регистрации (дубликат провайдера, частичная замена через
+	// WithOverride) возвращаются все сразу, а не теряются
+	if err := errors.Join(c.provideLifecycle(), c.provideDependencies()); err != nil {
+		return nil, fmt.Errorf("providers: %w", err)
+	}
+
+	if err := c.container.Invoke(func(interfaces.ConfigCommon) {}); err != nil {
+		return nil, fmt.Errorf("config: %w", dig.RootCause(err))
+	}
+
+	if err := c.provideModules(); err != nil {
+		return nil, fmt.Errorf("modules: %w", err)
+	}
+
+	// Ошибки связывания проявляются при старте любого бинарника,
+	// а не при первом Invoke конкретного компонента
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("dependency graph: %w", err)
+	}
+
+	// Логгер создаётся первым, чтобы его хук остановки выполнился последним
+	// и сбросил записи, сделанные при остановке остальных компонентов
+	if err := c.container.Invoke(func(*zap.Logger) {}); err != nil {
+		return nil, fmt.Errorf("logger: %w", dig.RootCause(err))
+	}
+
+	return c, nil
+}
+
+// provideLifecycle - регистрация менеджера жизненного цикла.
+// Провайдеры получают interfaces.Lifecycle и добавляют хуки созданных компонентов.
+func (c *Container) provideLifecycle() error {
+	return c.registry.Provide(func() interfaces.Lifecycle {
+		return c.lifecycle
 	})
+}
 
-	// Регистрируем RabbitMQ компоненты
-	c.container.Provide(func(configServer interfaces.ConfigServer) *rabbitmq.RabbitMQConnection {
-		conn := rabbitmq.NewRabbitMQConnection(configServer.GetRabbitMQURL())
-		c.closers = append(c.closers, adapter.NewRabbitMQConnectionCloser(conn))
-		return conn
+// provideDependencies - функция, регистрирующая зависимости.
+// Возвращает ошибки всех конструкторов, которые не удалось зарегистрировать.
+func (c *Container) provideDependencies() error {
+	p := &providers{registry: c.registry}
+	// Конфигурация регистрируется бинарником через ConfigProvider,
+	// инфраструктура зависит только от общих блоков interfaces.ConfigCommon
+	p.Provide(infra.NewStatus)
+	p.Provide(func(cf interfaces.ConfigCommon, reloader *config.Reloader, lc interfaces.Lifecycle, status interfaces.DependencyStatus, logger *zap.Logger) (*sqlx.DB, error) {
+		dbx, err := newDB(cf)
+		if err != nil {
+			return nil, err
+		}
+		// Необязательная БД (db.required: false) не мешает старту:
+		// проверка подключения повторяется в фоне до остановки контейнера
+		ctx, cancel := context.WithCancel(context.Background())
+		policy := infra.Policy{Required: cf.GetDBRequired(), ReconnectInterval: cf.GetDBReconnectInterval()}
+		if err := infra.ConnectPostgres(ctx, dbx, cf.GetDBConnectTimeout(), policy, status, logger); err != nil {
+			cancel()
+			dbx.Close()
+			return nil, err
+		}
+		// Настройки пула меняются на лету при перезагрузке конфигурации
+		reloader.Subscribe(func(cf interfaces.ConfigCommon) {
+			applyDBPool(dbx, cf)
+		})
+		// Хуки останавливаются в обратном порядке, а всё, что зависит от *sqlx.DB,
+		// создаётся после него - поэтому пул закрывается после своих потребителей
+		lc.Append(connectionHook(adapter.NewCloserHook("postgres", adapter.NewSQLXDBCloser(dbx))))
+		lc.Append(interfaces.Hook{
+			Name: "postgres.reconnect",
+			OnStop: func(ctx context.Context) error {
+				cancel()
+				return nil
+			},
+			Stage: interfaces.StageConnection,
+		})
+		return dbx, nil
+	})
+	p.Provide(db.NewTransactionManager)
+	// Use cases зависят от интерфейса: тесты заменяют его на MockTransactionManager
+	p.Provide(func(tm *db.TransactionManager) interfaces.TransactionManager { return tm })
+	p.Provide(http.NewAPIClient)
+	p.Provide(func(cf interfaces.ConfigCommon, reloader *config.Reloader, lc interfaces.Lifecycle) (*zap.Logger, error) {
+		level, err := zap.ParseAtomicLevel(cf.GetLogLevel())
+		if err != nil {
+			return nil, err
+		}
+		zapConfig := zap.NewProductionConfig()
+		zapConfig.Level = level
+		logger, err := zapConfig.Build()
+		if err != nil {
+			return nil, err
+		}
+		// Уровень логирования меняется на лету при перезагрузке конфигурации
+		reloader.Subscribe(func(cf interfaces.ConfigCommon) {
+			if l, err := zap.ParseAtomicLevel(cf.GetLogLevel()); err == nil {
+				level.SetLevel(l.Level())
+			}
+		})
+		lc.Append(adapter.NewLoggerHook("zap.logger", logger))
+		return logger, nil
 	})
 
-	c.container.Provide(func(conn *rabbitmq.RabbitMQConnection) *rabbitmq.RabbitMQProducer {
-		producer := rabbitmq.NewRabbitMQProducer(conn.Conn)
-		c.closers = append(c.closers, adapter.NewRabbitMQProducerCloser(producer))
-		return producer
+	// Регистрируем RabbitMQ компоненты. Провайдеры возвращают ошибку вместо
+	// завершения процесса; необязательный RabbitMQ (rabbitmq.required: false)
+	// подключается в фоне, пока недоступен - Publish возвращает infra.ErrUnavailable
+	p.Provide(func(cf interfaces.ConfigCommon, lc interfaces.Lifecycle, status interfaces.DependencyStatus, logger *zap.Logger) (*infra.RabbitMQConn, error) {
+		policy := infra.Policy{Required: cf.GetRabbitMQRequired(), ReconnectInterval: cf.GetRabbitMQReconnectInterval()}
+		conn, err := infra.NewRabbitMQConn(cf.GetRabbitMQURL(), policy, status, logger)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(connectionHook(adapter.NewCloserHook("rabbitmq.connection", conn)))
+		return conn, nil
 	})
 
-	c.container.Provide(func(conn *rabbitmq.RabbitMQConnection) *rabbitmq.RabbitMQConsumer {
-		// Используется стандартное имя очереди "default_queue"
-		// Для специфичных очередей создавайте отдельные consumer в сервисах
-		consumer := rabbitmq.NewRabbitMQConsumer(conn.Conn, "default_queue")
-		c.closers = append(c.closers, adapter.NewRabbitMQConsumerCloser(consumer))
-		return consumer
+	// Publisher открывает канал на актуальном соединении и переживает переподключение.
+	// Consumer очереди создаётся через infra.NewRabbitMQConsumer поверх *infra.RabbitMQConn.
+	p.Provide(func(conn *infra.RabbitMQConn) interfaces.Publisher {
+		return conn
 	})
+
+	c.provideHealthChecks(p)
+	return p.err()
+}
+
+// provideHealthChecks - проверки инфраструктуры для health эндпоинтов.
+// Модули добавляют свои проверки в ту же группу через module.Module.HealthChecks.
+// Проверку PostgreSQL регистрирует модуль healthcheck через HealthcheckRepository,
+// поэтому мок репозитория в тестах не требует БД.
+func (c *Container) provideHealthChecks(p *providers) {
+	// Без rabbitmq.url проверки RabbitMQ нет
+	p.Provide(func(conn *infra.RabbitMQConn, cf interfaces.ConfigCommon) []interfaces.HealthCheck {
+		if cf.GetRabbitMQURL() == "" {
+			return nil
+		}
+		return []interfaces.HealthCheck{infra.RabbitMQCheck(conn, cf.GetRabbitMQRequired())}
+	}, dig.Group(module.GroupHealthChecks+",flatten"))
+
+	// health.disk_min_free_mb: 0 отключает проверку диска; вне unix её нет
+	p.Provide(func(cf interfaces.ConfigCommon) []interfaces.HealthCheck {
+		if cf.GetHealthDiskMinFreeMB() == 0 || !infra.DiskCheckSupported {
+			return nil
+		}
+		return []interfaces.HealthCheck{infra.DiskCheck(cf.GetHealthDiskPath(), cf.GetHealthDiskMinFreeMB())}
+	}, dig.Group(module.GroupHealthChecks+",flatten"))
 }
 
-// provideUsecase - регистрация use case слоя
-func (c *Container) provideUsecase() {
+// providers регистрирует конструкторы и копит ошибки регистрации
+type providers struct {
+	registry *registry
+	errs     []error
 }
 
-// provideRepo - регистрация репозиториев
-// dig смотрит на сигнатуру NewHealthcheckRepository(db *sqlx.DB) interfaces.HealthcheckRepository
-// и автоматически регистрирует interfaces.HealthcheckRepository
-func (c *Container) provideRepo() {
-	c.container.Provide(repositories.NewHealthcheckRepository)
+func (p *providers) Provide(ctor interface{}, opts ...dig.ProvideOption) {
+	if err := p.registry.Provide(ctor, opts...); err != nil {
+		p.errs = append(p.errs, err)
+	}
+}
+
+func (p *providers) err() error {
+	return errors.Join(p.errs...)
 }
 
-// provideService - регистрация сервисов
-// dig смотрит на сигнатуру NewHealthcheckService(repo interfaces.HealthcheckRepository) interfaces.HealthcheckService
-// находит interfaces.HealthcheckRepository (уже зарегистрирован в provideRepo)
-// и автоматически вызывает конструктор с правильными аргументами
-func (c *Container) provideService() {
-	c.container.Provide(services.NewHealthcheckService)
+// connectionHook относит хук к этапу закрытия подключений: он выполняется
+// после остановки всех компонентов, которые могут использовать подключение
+func connectionHook(hook interfaces.Hook) interfaces.Hook {
+	hook.Stage = interfaces.StageConnection
+	return hook
+}
+
+// provideModules - регистрация фич из internal/modules.
+// Репозитории, сервисы, use cases и контроллеры объявляются в модуле фичи,
+// модули из modules.disabled конфигурации не загружаются.
+func (c *Container) provideModules() error {
+	var disabled []string
+	if err := c.container.Invoke(func(cf interfaces.ConfigCommon) {
+		disabled = cf.GetDisabledModules()
+	}); err != nil {
+		return err
+	}
+
+	enabled, err := module.Provide(c.registry, modules.All(), disabled)
+	if err != nil {
+		return err
+	}
+	c.modules = enabled
+	return nil
 }
 
-// provideController - регистрация контроллеров
-// dig смотрит на сигнатуру NewHealthcheckController(service interfaces.HealthcheckService) interfaces.HealthcheckController
-// находит interfaces.HealthcheckService (уже зарегистрирован в provideService)
-// и автоматически вызывает конструктор с правильными аргументами
-func (c *Container) provideController() {
-	c.container.Provide(controllers.NewHealthcheckController)
+// Modules возвращает включённые модули (например, для применения их миграций)
+func (c *Container) Modules() []module.Module {
+	return c.modules
 }
 
 // Invoke - функция для вызова и инжекта зависимостей
@@ -104,26 +250,43 @@ func (c *Container) Invoke(function interface{}) error {
 	return c.container.Invoke(function)
 }
 
-// Shutdown - graceful shutdown контейнера и всех зависимостей
-func (c *Container) Shutdown(ctx context.Context) error {
-	// Сначала синхронизируем логгеры (flush buffers)
-	for _, logger := range c.loggers {
-		if err := logger.Sync(); err != nil {
-			log.Printf("error syncing logger: %v", err)
-		}
-	}
+// Start запускает зарегистрированные компоненты в порядке зависимостей.
+// Вызывается после того, как бинарник разрешил все нужные ему компоненты.
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.Start(ctx)
+}
 
-	// Затем закрываем остальные компоненты в обратном порядке
-	for i := len(c.closers) - 1; i >= 0; i-- {
-		if err := c.closers[i].Close(); err != nil {
-			log.Printf("error closing component: %v", err)
-		}
-	}
+// StopComponents останавливает компоненты (consumers, producers, фоновые задачи),
+// не закрывая подключения к PostgreSQL и RabbitMQ. Дедлайн ctx действует на каждый хук.
+// Оставшиеся подключения закрывает Shutdown.
+func (c *Container) StopComponents(ctx context.Context) error {
+	return c.lifecycle.StopStage(ctx, interfaces.StageComponent)
+}
 
-	return nil
+// Stop останавливает компоненты и закрывает подключения, каждый этап со своим
+// дедлайном: shutdown.components_timeout и shutdown.connections_timeout
+func (c *Container) Stop(cf interfaces.ConfigShutdown) error {
+	componentsCtx, cancelComponents := context.WithTimeout(context.Background(), cf.GetShutdownComponentsTimeout())
+	defer cancelComponents()
+	componentsErr := c.StopComponents(componentsCtx)
+
+	connectionsCtx, cancelConnections := context.WithTimeout(context.Background(), cf.GetShutdownConnectionsTimeout())
+	defer cancelConnections()
+	return errors.Join(componentsErr, c.Shutdown(connectionsCtx))
+}
+
+// Shutdown - graceful shutdown контейнера и всех зависимостей.
+// Сначала останавливаются компоненты, затем закрываются подключения (interfaces.HookStage),
+// внутри этапа - в обратном порядке, дедлайн ctx действует на каждый хук.
+// Возвращает все ошибки остановки; зависшие компоненты - *lifecycle.HookError с Hung == true.
+func (c *Container) Shutdown(ctx context.Context) error {
+	return c.lifecycle.Stop(ctx)
 }
 
-// Close - закрытие контейнера без контекста (для defer)
+// Close - закрытие контейнера без контекста (для defer) с дедлайном defaultStopTimeout.
+// После Shutdown повторная остановка не выполняется.
 func (c *Container) Close() error {
-	return c.Shutdown(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStopTimeout)
+	defer cancel()
+	return c.Shutdown(ctx)
 }
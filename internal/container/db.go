@@ -0,0 +1,129 @@
+package container
+
+import (
+	"fmt"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newDB открывает пул соединений с PostgreSQL и применяет настройки из конфигурации.
+// connect_timeout и statement_timeout передаются драйверу через параметры DSN.
+func newDB(cf interfaces.ConfigDB) (*sqlx.DB, error) {
+	dsn, err := dsnWithParams(cf.GetDBDsn(), dbParams(cf))
+	if err != nil {
+		return nil, err
+	}
+
+	dbx, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	applyDBPool(dbx, cf)
+	return dbx, nil
+}
+
+// applyDBPool применяет настройки пула соединений из конфигурации
+func applyDBPool(dbx *sqlx.DB, cf interfaces.ConfigDB) {
+	dbx.SetMaxOpenConns(cf.GetDBMaxOpenConns())
+	dbx.SetMaxIdleConns(cf.GetDBMaxIdleConns())
+	dbx.SetConnMaxLifetime(cf.GetDBConnMaxLifetime())
+	dbx.SetConnMaxIdleTime(cf.GetDBConnMaxIdleTime())
+}
+
+// dbParams - параметры подключения lib/pq. Неизвестные драйверу параметры
+// (statement_timeout) передаются серверу как параметры сессии.
+func dbParams(cf interfaces.ConfigDB) map[string]string {
+	params := make(map[string]string)
+	if t := cf.GetDBConnectTimeout(); t > 0 {
+		// connect_timeout задаётся в целых секундах
+		params["connect_timeout"] = strconv.Itoa(int(math.Ceil(t.Seconds())))
+	}
+	if t := cf.GetDBStatementTimeout(); t > 0 {
+		params["statement_timeout"] = strconv.FormatInt(int64(t/time.Millisecond), 10)
+	}
+	return params
+}
+
+// dsnWithParams добавляет параметры к DSN в формате URL (postgres://...)
+// или key=value. Параметры, уже заданные в DSN явно, не переопределяются.
+func dsnWithParams(dsn string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return dsn, nil
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("parse db dsn: invalid URL")
+		}
+		q := u.Query()
+		for k, v := range params {
+			if !q.Has(k) {
+				q.Set(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	keys := dsnKeys(dsn)
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if !keys[k] {
+			dsn += " " + k + "=" + params[k]
+		}
+	}
+	return dsn, nil
+}
+
+// dsnKeys возвращает ключи DSN в формате key=value. Значение в одинарных
+// кавычках может содержать пробелы и "=", внутри значений \ экранирует символ,
+// поэтому пароль вида "x timeout=1" не считается параметром timeout.
+func dsnKeys(dsn string) map[string]bool {
+	keys := make(map[string]bool)
+	s := dsn
+	for {
+		s = strings.TrimLeft(s, " \t\n\r")
+		if s == "" {
+			return keys
+		}
+		end := strings.IndexAny(s, "= \t\n\r")
+		if end < 0 {
+			return keys
+		}
+		keys[s[:end]] = true
+		s = strings.TrimLeft(s[end:], " \t\n\r")
+		s = strings.TrimLeft(strings.TrimPrefix(s, "="), " \t\n\r")
+		s = skipDSNValue(s)
+	}
+}
+
+// skipDSNValue пропускает значение в начале s и возвращает остаток строки
+func skipDSNValue(s string) string {
+	quoted := strings.HasPrefix(s, "'")
+	if quoted {
+		s = s[1:]
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case quoted && c == '\'':
+			return s[i+1:]
+		case !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			return s[i:]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+
+	"go.uber.org/dig"
+)
+
+// Форматы выгрузки графа зависимостей (--print-graph)
+const (
+	GraphDOT  = "dot"
+	GraphJSON = "json"
+)
+
+// provider - конструктор, зарегистрированный в контейнере
+type provider struct {
+	ctor interface{}
+	opts []dig.ProvideOption
+	info dig.ProvideInfo
+}
+
+// registry регистрирует конструкторы в dig контейнере и запоминает их,
+// чтобы граф можно было проверить на копии в режиме dig.DryRun и выгрузить.
+// Реализует module.Provider.
+type registry struct {
+	container *dig.Container
+	providers []*provider
+	// overridden - типы, конструкторы которых заменены через WithOverride
+	overridden map[reflect.Type]bool
+}
+
+// Provide регистрирует конструктор. Конструктор, все результаты которого
+// заменены через WithOverride, пропускается.
+func (r *registry) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	if len(r.overridden) > 0 {
+		var replaced, kept int
+		for _, t := range outputTypes(ctor) {
+			if r.overridden[t] {
+				replaced++
+			} else {
+				kept++
+			}
+		}
+		if replaced > 0 && kept > 0 {
+			return fmt.Errorf("%s: only some results are overridden", funcLocation(ctor))
+		}
+		if replaced > 0 {
+			return nil
+		}
+	}
+	return r.provide(ctor, opts...)
+}
+
+// override регистрирует конструктор-замену; конструкторы тех же типов,
+// зарегистрированные после него, пропускаются
+func (r *registry) override(ctor interface{}, opts ...dig.ProvideOption) error {
+	if err := r.provide(ctor, opts...); err != nil {
+		return err
+	}
+	if r.overridden == nil {
+		r.overridden = make(map[reflect.Type]bool)
+	}
+	for _, t := range outputTypes(ctor) {
+		r.overridden[t] = true
+	}
+	return nil
+}
+
+func (r *registry) provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	p := &provider{ctor: ctor, opts: opts}
+	// FillProvideInfo не сохраняется в opts: при повторной регистрации
+	// в копии контейнера сведения перезаписывать не нужно
+	if err := r.container.Provide(ctor, append(opts[:len(opts):len(opts)], dig.FillProvideInfo(&p.info))...); err != nil {
+		return err
+	}
+	r.providers = append(r.providers, p)
+	return nil
+}
+
+// Validate проверяет, что зависимости каждого зарегистрированного конструктора
+// разрешаются, не вызывая конструкторы: граф повторяется в контейнере dig.DryRun,
+// и для каждого конструктора выполняется Invoke функции с теми же параметрами.
+// Возвращает ошибки всех конструкторов с неразрешимыми зависимостями.
+func (c *Container) Validate() error {
+	dry := dig.New(dig.DryRun(true))
+	for _, p := range c.registry.providers {
+		if err := dry.Provide(p.ctor, p.opts...); err != nil {
+			return fmt.Errorf("%s: %w", funcLocation(p.ctor), err)
+		}
+	}
+
+	var errs []error
+	for _, p := range c.registry.providers {
+		t := reflect.TypeOf(p.ctor)
+		if t.NumIn() == 0 {
+			continue
+		}
+		in := make([]reflect.Type, t.NumIn())
+		for i := range in {
+			in[i] = t.In(i)
+		}
+		fn := reflect.MakeFunc(reflect.FuncOf(in, nil, t.IsVariadic()), func([]reflect.Value) []reflect.Value {
+			return nil
+		})
+		if err := dry.Invoke(fn.Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", funcLocation(p.ctor), dig.RootCause(err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// graphNode - конструктор в JSON выгрузке графа
+type graphNode struct {
+	Constructor string   `json:"constructor"`
+	Inputs      []string `json:"inputs"`
+	Outputs     []string `json:"outputs"`
+}
+
+// PrintGraph выгружает граф зависимостей в формате GraphDOT (dig.Visualize, для Graphviz)
+// или GraphJSON (список конструкторов с типами параметров и результатов)
+func (c *Container) PrintGraph(w io.Writer, format string) error {
+	switch format {
+	case GraphDOT:
+		return dig.Visualize(c.container, w)
+	case GraphJSON:
+		nodes := make([]graphNode, 0, len(c.registry.providers))
+		for _, p := range c.registry.providers {
+			node := graphNode{
+				Constructor: funcName(p.ctor),
+				Inputs:      []string{},
+				Outputs:     []string{},
+			}
+			for _, in := range p.info.Inputs {
+				node.Inputs = append(node.Inputs, in.String())
+			}
+			for _, out := range p.info.Outputs {
+				node.Outputs = append(node.Outputs, out.String())
+			}
+			nodes = append(nodes, node)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{"providers": nodes})
+	default:
+		return fmt.Errorf("unknown graph format %q, expected %s or %s", format, GraphDOT, GraphJSON)
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// outputTypes возвращает типы результатов конструктора, кроме error
+func outputTypes(ctor interface{}) []reflect.Type {
+	t := reflect.TypeOf(ctor)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil
+	}
+	var types []reflect.Type
+	for i := 0; i < t.NumOut(); i++ {
+		if out := t.Out(i); out != errorType {
+			types = append(types, out)
+		}
+	}
+	return types
+}
+
+// funcName возвращает полное имя функции (анонимные конструкторы - вида pkg.Func.func1)
+func funcName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", fn)
+}
+
+// funcLocation возвращает имя функции и место её объявления
+func funcLocation(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return fmt.Sprintf("%T", fn)
+	}
+	file, line := f.FileLine(f.Entry())
+	return fmt.Sprintf("%s (%s:%d)", f.Name(), file, line)
+}
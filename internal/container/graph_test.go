@@ -0,0 +1,30 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type (
+	first  struct{}
+	second struct{}
+)
+
+func TestRegistryPartialOverride(t *testing.T) {
+	r := &registry{container: dig.New()}
+	if err := r.override(func() *first { return &first{} }); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Provide(func() (*first, *second) { return &first{}, &second{} })
+	if err == nil || !strings.Contains(err.Error(), "only some results are overridden") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Полностью заменённый конструктор пропускается без ошибки
+	if err := r.Provide(func() *first { return nil }); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,152 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/config"
+	serverconfig "github.com/SmirnovND/gobase/internal/config/server"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// override - конструктор, заменяющий провайдер того же типа
+type override struct {
+	ctor interface{}
+	opts []dig.ProvideOption
+}
+
+// testOptions - настройки NewTestContainer
+type testOptions struct {
+	configProvider ConfigProvider
+	overrides      []override
+}
+
+// TestOption настраивает NewTestContainer
+type TestOption func(o *testOptions)
+
+// WithConfig задаёт конфигурацию в памяти вместо чтения файла и окружения
+func WithConfig(cf interfaces.ConfigCommon) TestOption {
+	return func(o *testOptions) {
+		o.configProvider = StaticConfig(cf)
+	}
+}
+
+// WithConfigProvider задаёт конфигурацию бинарника (ServerConfig, ConsumerConfig, CronConfig)
+func WithConfigProvider(configProvider ConfigProvider) TestOption {
+	return func(o *testOptions) {
+		o.configProvider = configProvider
+	}
+}
+
+// WithOverride заменяет провайдер типов, которые возвращает ctor.
+// Подходит для любого компонента: инфраструктуры, конфигурации, репозиториев модулей.
+//
+//	container.WithOverride(func() interfaces.HealthcheckRepository {
+//	    return &interfaces.MockHealthcheckRepository{}
+//	})
+func WithOverride(ctor interface{}, opts ...dig.ProvideOption) TestOption {
+	return func(o *testOptions) {
+		o.overrides = append(o.overrides, override{ctor: ctor, opts: opts})
+	}
+}
+
+// NewTestContainer собирает контейнер для тестов. Отличия от NewContainer:
+//   - конфигурация по умолчанию - значения по умолчанию серверной конфигурации
+//     без файла, окружения и валидации (WithConfig, WithConfigProvider);
+//   - логгер - zap.NewNop (можно заменить через WithOverride);
+//   - любой провайдер заменяется через WithOverride, из нескольких замен
+//     одного типа действует последняя.
+//
+// Граф проверяется так же, как в NewContainer. Инфраструктура (PostgreSQL, RabbitMQ)
+// создаётся лениво, только если тест разрешает зависящий от неё компонент,
+// поэтому замена репозиториев на моки не требует поднятой БД.
+func NewTestContainer(opts ...TestOption) (*Container, error) {
+	o := &testOptions{
+		overrides: []override{{ctor: zap.NewNop}},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.configProvider == nil {
+		cf, err := serverconfig.Load(nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		o.configProvider = StaticConfig(cf)
+	}
+
+	return newContainer(o.configProvider, dedupOverrides(o.overrides))
+}
+
+// MustResolve собирает тестовый контейнер с opts и возвращает из него компонент
+// типа T. Контейнер закрывается по завершении теста, ошибка сборки или
+// разрешения завершает тест.
+//
+//	repo := container.MustResolve[interfaces.UserRepository](t,
+//	    container.WithOverride(func() *sqlx.DB { return db }),
+//	)
+func MustResolve[T any](t testing.TB, opts ...TestOption) T {
+	t.Helper()
+	c, err := NewTestContainer(opts...)
+	if err != nil {
+		t.Fatalf("failed to build container: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	var resolved T
+	if err := c.Invoke(func(v T) { resolved = v }); err != nil {
+		t.Fatalf("failed to resolve %s: %v", reflect.TypeOf((*T)(nil)).Elem(), err)
+	}
+	return resolved
+}
+
+// dedupOverrides оставляет для каждого типа последнюю замену,
+// чтобы тест мог переопределить замены по умолчанию (логгер)
+func dedupOverrides(overrides []override) []override {
+	var result []override
+	for i, o := range overrides {
+		replaced := false
+		for _, later := range overrides[i+1:] {
+			for _, t := range outputTypes(later.ctor) {
+				for _, own := range outputTypes(o.ctor) {
+					replaced = replaced || t == own
+				}
+			}
+		}
+		if !replaced {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// StaticConfig - ConfigProvider с готовой конфигурацией. cf регистрируется
+// как interfaces.ConfigCommon и как интерфейсы бинарников, которые он реализует.
+// Горячая перезагрузка не поддерживается.
+func StaticConfig(cf interfaces.ConfigCommon) ConfigProvider {
+	return func(c module.Provider) error {
+		ctors := []interface{}{
+			func() interfaces.ConfigCommon { return cf },
+			func() *config.Reloader { return config.NewReloader(nil, nil) },
+		}
+		if s, ok := cf.(interfaces.ConfigServer); ok {
+			ctors = append(ctors, func() interfaces.ConfigServer { return s })
+		}
+		if s, ok := cf.(interfaces.ConfigConsumer); ok {
+			ctors = append(ctors, func() interfaces.ConfigConsumer { return s })
+		}
+		if s, ok := cf.(interfaces.ConfigCron); ok {
+			ctors = append(ctors, func() interfaces.ConfigCron { return s })
+		}
+		for _, ctor := range ctors {
+			if err := c.Provide(ctor); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
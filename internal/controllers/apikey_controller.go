@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/middleware"
+	"github.com/SmirnovND/gobase/internal/request"
+	"github.com/SmirnovND/gobase/internal/response"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Действие и ресурс, разрешение на которые нужно для управления API ключами
+const (
+	apiKeyManageAction   = "manage"
+	apiKeyManageResource = "api_keys"
+)
+
+type apiKeyController struct {
+	apiKeyService interfaces.APIKeyService
+	authz         interfaces.Authorizer
+	// gracePeriod - сколько действует прежний секрет после ротации (auth.api_key.rotation_grace_period)
+	gracePeriod time.Duration
+	logger      *zap.Logger
+}
+
+func NewAPIKeyController(apiKeyService interfaces.APIKeyService, authz interfaces.Authorizer, cf interfaces.ConfigServer, logger *zap.Logger) interfaces.APIKeyController {
+	return &apiKeyController{
+		apiKeyService: apiKeyService,
+		authz:         authz,
+		gracePeriod:   cf.GetAPIKeyRotationGracePeriod(),
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes подключает API управления ключами: нужен аутентифицированный
+// субъект с разрешением manage на api_keys (RBAC)
+func (c *apiKeyController) RegisterRoutes(r chi.Router) {
+	r.Route("/api/v1/admin/api-keys", func(r chi.Router) {
+		r.Use(middleware.RequireAuth, middleware.Authorize(c.authz, apiKeyManageAction, apiKeyManageResource, c.logger))
+
+		r.Get("/", c.HandleList)
+		r.Post("/", c.HandleCreate)
+		r.Get("/{id}", c.HandleGet)
+		r.Post("/{id}/rotate", c.HandleRotate)
+		r.Delete("/{id}", c.HandleRevoke)
+	})
+}
+
+// ListAPIKeysRequest - фильтры и страница списка ключей
+type ListAPIKeysRequest struct {
+	Name    string `json:"-" query:"name"`
+	Revoked bool   `json:"-" query:"revoked"`
+	Page    int    `json:"-" query:"page" default:"1" validate:"min=1"`
+	PerPage int    `json:"-" query:"per_page" default:"20" validate:"min=1,max=100"`
+}
+
+// CreateAPIKeyRequest - выпуск ключа
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=100" example:"billing-sync"`
+	Scopes []string `json:"scopes" validate:"max=50" example:"orders:read"`
+	// ExpiresAt - срок действия, пустой - бессрочный ключ
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Validate - срок действия нового ключа должен быть в будущем
+func (req CreateAPIKeyRequest) Validate() error {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return apperrors.Validation(apperrors.FieldError{Field: "expires_at", Code: "invalid", Message: "must be in the future"})
+	}
+	return nil
+}
+
+// APIKeyRequest - ключ в пути запроса
+type APIKeyRequest struct {
+	ID int64 `json:"-" path:"id" validate:"required"`
+}
+
+// APIKeyPage - тело ответа HandleList (response.PageResponse) для Swagger
+type APIKeyPage struct {
+	Items      []domain.APIKey     `json:"items"`
+	Pagination response.Pagination `json:"pagination"`
+}
+
+// HandleList godoc
+// @Summary      Список API ключей
+// @Description  Новые ключи - первыми; отозванные - только с revoked=true
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        name      query  string  false  "Имя ключа"
+// @Param        revoked   query  bool    false  "Включить отозванные ключи"
+// @Param        page      query  int     false  "Номер страницы"  default(1)
+// @Param        per_page  query  int     false  "Размер страницы"  default(20)  maximum(100)
+// @Success      200  {object}  APIKeyPage
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/api-keys [get]
+func (c *apiKeyController) HandleList(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[ListAPIKeysRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+
+	keys, total, err := c.apiKeyService.List(r.Context(), domain.APIKeyFilter{
+		Name:        req.Name,
+		WithRevoked: req.Revoked,
+		Limit:       req.PerPage,
+		Offset:      (req.Page - 1) * req.PerPage,
+	})
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Paginated(w, r, keys, response.Page{Page: req.Page, PerPage: req.PerPage, Total: total})
+}
+
+// HandleGet godoc
+// @Summary      API ключ
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор ключа"
+// @Success      200  {object}  domain.APIKey
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "api_key_not_found"
+// @Router       /api/v1/admin/api-keys/{id} [get]
+func (c *apiKeyController) HandleGet(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[APIKeyRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	key, err := c.apiKeyService.Get(r.Context(), req.ID)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.JSON(w, r, http.StatusOK, key)
+}
+
+// HandleCreate godoc
+// @Summary      Выпустить API ключ
+// @Description  Ключ (поле key) возвращается только в этом ответе. Роли ключу выдаются
+// @Description  привязками RBAC с subject_kind api_key и subject = id ключа
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        key  body  CreateAPIKeyRequest  true  "Ключ"
+// @Success      201  {object}  domain.IssuedAPIKey
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/api-keys [post]
+func (c *apiKeyController) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[CreateAPIKeyRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	key, err := c.apiKeyService.Create(r.Context(), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Created(w, r, fmt.Sprintf("/api/v1/admin/api-keys/%d", key.ID), key)
+}
+
+// HandleRotate godoc
+// @Summary      Перевыпустить API ключ
+// @Description  Новый секрет с тем же id, scopes и ролями. Старый секрет действует ещё
+// @Description  auth.api_key.rotation_grace_period (previous_expires_at), при 0 - перестаёт сразу.
+// @Description  Истёкший ключ не ротируется: выпустите новый
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор ключа"
+// @Success      200  {object}  domain.IssuedAPIKey
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "api_key_not_found"
+// @Failure      409  {object}  apperrors.Problem  "api_key_revoked, api_key_expired"
+// @Router       /api/v1/admin/api-keys/{id}/rotate [post]
+func (c *apiKeyController) HandleRotate(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[APIKeyRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	key, err := c.apiKeyService.Rotate(r.Context(), req.ID, c.gracePeriod)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.JSON(w, r, http.StatusOK, key)
+}
+
+// HandleRevoke godoc
+// @Summary      Отозвать API ключ
+// @Description  Ключ остаётся в списке с revoked_at; повторный отзыв - 204
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор ключа"
+// @Success      204
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "api_key_not_found"
+// @Router       /api/v1/admin/api-keys/{id} [delete]
+func (c *apiKeyController) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[APIKeyRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	if err := c.apiKeyService.Revoke(r.Context(), req.ID); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// fail отвечает ошибкой сервиса; внутренние ошибки пишет в журнал
+func (c *apiKeyController) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if apperrors.As(err).Kind == apperrors.KindInternal {
+		c.logger.Error("API key request failed", zap.String("path", r.URL.Path), zap.Error(err))
+	}
+	apperrors.Write(w, r, err)
+}
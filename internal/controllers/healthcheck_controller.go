@@ -1,9 +1,12 @@
 package controllers
 
 import (
-	"encoding/json"
+	"github.com/SmirnovND/gobase/internal/domain"
 	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/response"
+	"github.com/go-chi/chi/v5"
 	"net/http"
+	"strconv"
 )
 
 type healthcheckController struct {
@@ -16,28 +19,82 @@ func NewHealthcheckController(healthcheckService interfaces.HealthcheckService)
 	}
 }
 
+// RegisterRoutes подключает пробы в корне: балансировщики и оркестраторы
+// ожидают их вне версионированного /api
+func (hc *healthcheckController) RegisterRoutes(r chi.Router) {
+	r.Get("/ping", hc.HandlePing)
+	r.Get("/livez", hc.HandleLivez)
+	r.Get("/readyz", hc.HandleReadyz)
+	r.Get("/startupz", hc.HandleStartupz)
+}
+
 // HandlePing godoc
 // @Summary      Проверка здоровья сервиса
-// @Description  Проверяет доступность сервиса и подключение к базе данных
+// @Description  Совместимый алиас /readyz
 // @Tags         healthcheck
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "OK"
-// @Failure      500  {object}  map[string]interface{}  "Service unhealthy"
+// @Param        fresh  query  bool  false  "Выполнить проверки сейчас (не чаще health.fresh_interval), а не вернуть результат фонового обновления"
+// @Success      200  {object}  domain.HealthReport  "up или degraded"
+// @Failure      503  {object}  domain.HealthReport  "down"
 // @Router       /ping [get]
 func (hc *healthcheckController) HandlePing(w http.ResponseWriter, r *http.Request) {
-	status, err := hc.healthcheckService.Check(r.Context())
+	hc.handleProbe(w, r, domain.ProbeReadiness)
+}
 
-	w.Header().Set("Content-Type", "application/json")
+// HandleLivez godoc
+// @Summary      Liveness проба
+// @Description  Процесс жив; внешние зависимости не проверяются
+// @Tags         healthcheck
+// @Produce      json
+// @Param        fresh  query  bool  false  "Выполнить проверки сейчас (не чаще health.fresh_interval), а не вернуть результат фонового обновления"
+// @Success      200  {object}  domain.HealthReport  "up или degraded"
+// @Failure      503  {object}  domain.HealthReport  "down"
+// @Router       /livez [get]
+func (hc *healthcheckController) HandleLivez(w http.ResponseWriter, r *http.Request) {
+	hc.handleProbe(w, r, domain.ProbeLiveness)
+}
 
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status": "error",
-			"error":  err.Error(),
-		})
-		return
+// HandleReadyz godoc
+// @Summary      Readiness проба
+// @Description  Экземпляр готов принимать трафик: проверяет PostgreSQL, RabbitMQ, диск и проверки модулей
+// @Tags         healthcheck
+// @Produce      json
+// @Param        fresh  query  bool  false  "Выполнить проверки сейчас (не чаще health.fresh_interval), а не вернуть результат фонового обновления"
+// @Success      200  {object}  domain.HealthReport  "up или degraded"
+// @Failure      503  {object}  domain.HealthReport  "down"
+// @Router       /readyz [get]
+func (hc *healthcheckController) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	hc.handleProbe(w, r, domain.ProbeReadiness)
+}
+
+// HandleStartupz godoc
+// @Summary      Startup проба
+// @Description  Экземпляр завершил запуск: БД доступна, миграции применены
+// @Tags         healthcheck
+// @Produce      json
+// @Param        fresh  query  bool  false  "Выполнить проверки сейчас (не чаще health.fresh_interval), а не вернуть результат фонового обновления"
+// @Success      200  {object}  domain.HealthReport  "up или degraded"
+// @Failure      503  {object}  domain.HealthReport  "down"
+// @Router       /startupz [get]
+func (hc *healthcheckController) HandleStartupz(w http.ResponseWriter, r *http.Request) {
+	hc.handleProbe(w, r, domain.ProbeStartup)
+}
+
+// handleProbe отвечает отчётом пробы: 503 при down, иначе 200 -
+// экземпляр с отказавшей необязательной зависимостью продолжает получать трафик.
+// ?fresh=1 выполняет проверки по запросу вместо результата фонового обновления,
+// но не чаще health.fresh_interval (HealthcheckService.CheckFresh).
+func (hc *healthcheckController) handleProbe(w http.ResponseWriter, r *http.Request, probe domain.Probe) {
+	var report domain.HealthReport
+	if fresh, _ := strconv.ParseBool(r.URL.Query().Get("fresh")); fresh {
+		report = hc.healthcheckService.CheckFresh(r.Context(), probe)
+	} else {
+		report = hc.healthcheckService.Check(r.Context(), probe)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(status)
+	status := http.StatusOK
+	if report.Status == domain.HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	response.JSON(w, r, status, report)
 }
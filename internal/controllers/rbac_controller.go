@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/middleware"
+	"github.com/SmirnovND/gobase/internal/request"
+	"github.com/SmirnovND/gobase/internal/response"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Действие и ресурс, разрешение на которые нужно для администрирования RBAC
+const (
+	rbacManageAction   = "manage"
+	rbacManageResource = "rbac"
+)
+
+type rbacController struct {
+	rbacService interfaces.RBACService
+	logger      *zap.Logger
+}
+
+func NewRBACController(rbacService interfaces.RBACService, logger *zap.Logger) interfaces.RBACController {
+	return &rbacController{
+		rbacService: rbacService,
+		logger:      logger,
+	}
+}
+
+// RegisterRoutes подключает API администрирования: нужен аутентифицированный
+// субъект с разрешением manage на rbac (например, роль admin)
+func (c *rbacController) RegisterRoutes(r chi.Router) {
+	r.Route("/api/v1/admin/rbac", func(r chi.Router) {
+		r.Use(middleware.RequireAuth, middleware.Authorize(c.rbacService, rbacManageAction, rbacManageResource, c.logger))
+
+		r.Get("/roles", c.HandleListRoles)
+		r.Post("/roles", c.HandleCreateRole)
+		r.Get("/roles/{name}", c.HandleGetRole)
+		r.Delete("/roles/{name}", c.HandleDeleteRole)
+		r.Post("/roles/{name}/permissions", c.HandleAddPermission)
+		r.Delete("/roles/{name}/permissions/{id}", c.HandleRemovePermission)
+
+		r.Get("/bindings", c.HandleListBindings)
+		r.Post("/bindings", c.HandleCreateBinding)
+		r.Delete("/bindings/{id}", c.HandleDeleteBinding)
+	})
+}
+
+// RoleRequest - роль в пути запроса
+type RoleRequest struct {
+	Name string `json:"-" path:"name" validate:"required,max=100"`
+}
+
+// CreateRoleRequest - тело создания роли
+type CreateRoleRequest struct {
+	Name        string `json:"name" validate:"required,max=100" example:"order-manager"`
+	Description string `json:"description" validate:"max=500" example:"Управление заказами"`
+}
+
+// AddPermissionRequest - разрешение, добавляемое роли
+type AddPermissionRequest struct {
+	Role     string `json:"-" path:"name" validate:"required,max=100"`
+	Action   string `json:"action" validate:"required,max=100" example:"write"`
+	Resource string `json:"resource" validate:"required,max=200" example:"orders:*"`
+}
+
+// RemovePermissionRequest - разрешение роли в пути запроса
+type RemovePermissionRequest struct {
+	Role string `json:"-" path:"name" validate:"required,max=100"`
+	ID   int64  `json:"-" path:"id" validate:"required"`
+}
+
+// ListBindingsRequest - фильтры и страница списка привязок
+type ListBindingsRequest struct {
+	SubjectKind string `json:"-" query:"subject_kind" validate:"oneof=user api_key"`
+	Subject     string `json:"-" query:"subject"`
+	Role        string `json:"-" query:"role"`
+	Page        int    `json:"-" query:"page" default:"1" validate:"min=1"`
+	PerPage     int    `json:"-" query:"per_page" default:"20" validate:"min=1,max=100"`
+}
+
+// CreateBindingRequest - выдача роли субъекту
+type CreateBindingRequest struct {
+	SubjectKind string `json:"subject_kind" validate:"required,oneof=user api_key" example:"user"`
+	Subject     string `json:"subject" validate:"required,max=200" example:"42"`
+	Role        string `json:"role" validate:"required,max=100" example:"order-manager"`
+}
+
+// BindingRequest - привязка в пути запроса
+type BindingRequest struct {
+	ID int64 `json:"-" path:"id" validate:"required"`
+}
+
+// RoleBindingPage - тело ответа HandleListBindings (response.PageResponse) для Swagger:
+// swag не разбирает generic типы из других пакетов без parseDependency
+type RoleBindingPage struct {
+	Items      []domain.RoleBinding `json:"items"`
+	Pagination response.Pagination  `json:"pagination"`
+}
+
+// HandleListRoles godoc
+// @Summary      Список ролей
+// @Description  Роли с разрешениями, упорядоченные по имени
+// @Tags         rbac
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200  {array}   domain.Role
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Router       /api/v1/admin/rbac/roles [get]
+func (c *rbacController) HandleListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := c.rbacService.ListRoles(r.Context())
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	if roles == nil {
+		roles = []domain.Role{}
+	}
+	response.JSON(w, r, http.StatusOK, roles)
+}
+
+// HandleGetRole godoc
+// @Summary      Роль
+// @Tags         rbac
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        name  path  string  true  "Имя роли"
+// @Success      200  {object}  domain.Role
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "role_not_found"
+// @Router       /api/v1/admin/rbac/roles/{name} [get]
+func (c *rbacController) HandleGetRole(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[RoleRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	role, err := c.rbacService.GetRole(r.Context(), req.Name)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.JSON(w, r, http.StatusOK, role)
+}
+
+// HandleCreateRole godoc
+// @Summary      Создать роль
+// @Tags         rbac
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        role  body  CreateRoleRequest  true  "Роль"
+// @Success      201  {object}  domain.Role
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      409  {object}  apperrors.Problem  "role_exists"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/rbac/roles [post]
+func (c *rbacController) HandleCreateRole(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[CreateRoleRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	role := &domain.Role{Name: req.Name, Description: req.Description}
+	if err := c.rbacService.CreateRole(r.Context(), role); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Created(w, r, "/api/v1/admin/rbac/roles/"+role.Name, role)
+}
+
+// HandleDeleteRole godoc
+// @Summary      Удалить роль
+// @Description  Удаляет роль вместе с её разрешениями и привязками
+// @Tags         rbac
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        name  path  string  true  "Имя роли"
+// @Success      204
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "role_not_found"
+// @Router       /api/v1/admin/rbac/roles/{name} [delete]
+func (c *rbacController) HandleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[RoleRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	if err := c.rbacService.DeleteRole(r.Context(), req.Name); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// HandleAddPermission godoc
+// @Summary      Добавить разрешение роли
+// @Description  "*" - любое действие или ресурс, "*" в конце - префикс ресурса (orders:*)
+// @Tags         rbac
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        name        path  string                true  "Имя роли"
+// @Param        permission  body  AddPermissionRequest  true  "Разрешение"
+// @Success      201  {object}  domain.Permission
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "role_not_found"
+// @Failure      409  {object}  apperrors.Problem  "permission_exists"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/rbac/roles/{name}/permissions [post]
+func (c *rbacController) HandleAddPermission(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[AddPermissionRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	p := &domain.Permission{Action: req.Action, Resource: req.Resource}
+	if err := c.rbacService.AddPermission(r.Context(), req.Role, p); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Created(w, r, fmt.Sprintf("/api/v1/admin/rbac/roles/%s/permissions/%d", req.Role, p.ID), p)
+}
+
+// HandleRemovePermission godoc
+// @Summary      Удалить разрешение роли
+// @Tags         rbac
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        name  path  string  true  "Имя роли"
+// @Param        id    path  int     true  "Идентификатор разрешения"
+// @Success      204
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "permission_not_found"
+// @Router       /api/v1/admin/rbac/roles/{name}/permissions/{id} [delete]
+func (c *rbacController) HandleRemovePermission(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[RemovePermissionRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	if err := c.rbacService.RemovePermission(r.Context(), req.Role, req.ID); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// HandleListBindings godoc
+// @Summary      Список привязок ролей
+// @Tags         rbac
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        subject_kind  query  string  false  "Вид субъекта"  Enums(user, api_key)
+// @Param        subject       query  string  false  "Субъект"
+// @Param        role          query  string  false  "Имя роли"
+// @Param        page          query  int     false  "Номер страницы"  default(1)
+// @Param        per_page      query  int     false  "Размер страницы"  default(20)  maximum(100)
+// @Success      200  {object}  RoleBindingPage
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/rbac/bindings [get]
+func (c *rbacController) HandleListBindings(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[ListBindingsRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+
+	bindings, total, err := c.rbacService.ListBindings(r.Context(), domain.RoleBindingFilter{
+		SubjectKind: req.SubjectKind,
+		Subject:     req.Subject,
+		Role:        req.Role,
+		Limit:       req.PerPage,
+		Offset:      (req.Page - 1) * req.PerPage,
+	})
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Paginated(w, r, bindings, response.Page{Page: req.Page, PerPage: req.PerPage, Total: total})
+}
+
+// HandleCreateBinding godoc
+// @Summary      Выдать роль субъекту
+// @Description  subject - sub токена для user или идентификатор API ключа для api_key
+// @Tags         rbac
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        binding  body  CreateBindingRequest  true  "Привязка"
+// @Success      201  {object}  domain.RoleBinding
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "role_not_found"
+// @Failure      409  {object}  apperrors.Problem  "role_binding_exists"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/admin/rbac/bindings [post]
+func (c *rbacController) HandleCreateBinding(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[CreateBindingRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	b := &domain.RoleBinding{SubjectKind: req.SubjectKind, Subject: req.Subject, Role: req.Role}
+	if err := c.rbacService.Bind(r.Context(), b); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Created(w, r, fmt.Sprintf("/api/v1/admin/rbac/bindings/%d", b.ID), b)
+}
+
+// HandleDeleteBinding godoc
+// @Summary      Отозвать роль у субъекта
+// @Tags         rbac
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор привязки"
+// @Success      204
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem
+// @Failure      404  {object}  apperrors.Problem  "role_binding_not_found"
+// @Router       /api/v1/admin/rbac/bindings/{id} [delete]
+func (c *rbacController) HandleDeleteBinding(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[BindingRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	if err := c.rbacService.Unbind(r.Context(), req.ID); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// fail отвечает ошибкой сервиса; внутренние ошибки пишет в журнал,
+// клиент видит только общее сообщение
+func (c *rbacController) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if apperrors.As(err).Kind == apperrors.KindInternal {
+		c.logger.Error("RBAC request failed", zap.String("path", r.URL.Path), zap.Error(err))
+	}
+	apperrors.Write(w, r, err)
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/request"
+)
+
+// Теги validate/default/query/path всех запросов должны разбираться без ошибок:
+// иначе соответствующий handler отвечает 500 на каждый вызов
+func TestRequestRules(t *testing.T) {
+	checks := map[string]func() error{
+		"ListUsersRequest":        request.CheckRules[ListUsersRequest],
+		"CreateUserRequest":       request.CheckRules[CreateUserRequest],
+		"UpdateUserRequest":       request.CheckRules[UpdateUserRequest],
+		"UserRequest":             request.CheckRules[UserRequest],
+		"ListAPIKeysRequest":      request.CheckRules[ListAPIKeysRequest],
+		"CreateAPIKeyRequest":     request.CheckRules[CreateAPIKeyRequest],
+		"APIKeyRequest":           request.CheckRules[APIKeyRequest],
+		"RoleRequest":             request.CheckRules[RoleRequest],
+		"CreateRoleRequest":       request.CheckRules[CreateRoleRequest],
+		"AddPermissionRequest":    request.CheckRules[AddPermissionRequest],
+		"RemovePermissionRequest": request.CheckRules[RemovePermissionRequest],
+		"ListBindingsRequest":     request.CheckRules[ListBindingsRequest],
+		"CreateBindingRequest":    request.CheckRules[CreateBindingRequest],
+		"BindingRequest":          request.CheckRules[BindingRequest],
+	}
+	for name, check := range checks {
+		if err := check(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/middleware"
+	"github.com/SmirnovND/gobase/internal/request"
+	"github.com/SmirnovND/gobase/internal/response"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type userController struct {
+	userUsecase interfaces.UserUsecase
+	logger      *zap.Logger
+}
+
+func NewUserController(userUsecase interfaces.UserUsecase, logger *zap.Logger) interfaces.UserController {
+	return &userController{
+		userUsecase: userUsecase,
+		logger:      logger,
+	}
+}
+
+// RegisterRoutes подключает CRUD пользователей. Права на действия проверяет
+// use case (Authorizer), здесь - только аутентификация
+func (c *userController) RegisterRoutes(r chi.Router) {
+	r.Route("/api/v1/users", func(r chi.Router) {
+		r.Use(middleware.RequireAuth)
+
+		r.Get("/", c.HandleList)
+		r.Post("/", c.HandleCreate)
+		r.Get("/{id}", c.HandleGet)
+		r.Patch("/{id}", c.HandleUpdate)
+		r.Delete("/{id}", c.HandleDelete)
+	})
+}
+
+// ListUsersRequest - фильтры, сортировка и страница списка пользователей
+type ListUsersRequest struct {
+	Query       string     `json:"-" query:"q" validate:"max=100"`
+	Email       string     `json:"-" query:"email" validate:"email"`
+	CreatedFrom *time.Time `json:"-" query:"created_from"`
+	CreatedTo   *time.Time `json:"-" query:"created_to"`
+	Sort        string     `json:"-" query:"sort" validate:"oneof=id -id name -name created_at -created_at"`
+	Page        int        `json:"-" query:"page" default:"1" validate:"min=1"`
+	PerPage     int        `json:"-" query:"per_page" default:"20" validate:"min=1,max=100"`
+}
+
+// CreateUserRequest - создание пользователя
+type CreateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=100" example:"Иван Петров"`
+	Email string `json:"email" validate:"required,email,max=254" example:"ivan@example.com"`
+}
+
+// UpdateUserRequest - частичное изменение: отсутствующие поля не меняются
+type UpdateUserRequest struct {
+	ID    int64   `json:"-" path:"id" validate:"required"`
+	Name  *string `json:"name" validate:"min=1,max=100" example:"Иван Петров"`
+	Email *string `json:"email" validate:"email,max=254" example:"ivan@example.com"`
+}
+
+// Validate - пустой PATCH скорее ошибка клиента, чем намерение
+func (req UpdateUserRequest) Validate() error {
+	if req.Name == nil && req.Email == nil {
+		return apperrors.Validation(apperrors.FieldError{Field: "name", Code: "required", Message: "name or email is required"})
+	}
+	return nil
+}
+
+// UserRequest - пользователь в пути запроса
+type UserRequest struct {
+	ID int64 `json:"-" path:"id" validate:"required"`
+}
+
+// UserPage - тело ответа HandleList (response.PageResponse) для Swagger
+type UserPage struct {
+	Items      []domain.User       `json:"items"`
+	Pagination response.Pagination `json:"pagination"`
+}
+
+// HandleList godoc
+// @Summary      Список пользователей
+// @Description  Фильтры объединяются через И. Нужно разрешение read на users
+// @Tags         users
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        q             query  string  false  "Подстрока имени или email"
+// @Param        email         query  string  false  "Email без учёта регистра"
+// @Param        created_from  query  string  false  "Создан не раньше (RFC 3339)"  format(date-time)
+// @Param        created_to    query  string  false  "Создан раньше (RFC 3339)"  format(date-time)
+// @Param        sort          query  string  false  "Сортировка, - по убыванию"  Enums(id, -id, name, -name, created_at, -created_at)  default(id)
+// @Param        page          query  int     false  "Номер страницы"  default(1)
+// @Param        per_page      query  int     false  "Размер страницы"  default(20)  maximum(100)
+// @Success      200  {object}  UserPage
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem  "permission_denied"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/users [get]
+func (c *userController) HandleList(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[ListUsersRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+
+	users, total, err := c.userUsecase.List(r.Context(), domain.UserFilter{
+		Query:       req.Query,
+		Email:       req.Email,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Sort:        req.Sort,
+		Limit:       req.PerPage,
+		Offset:      (req.Page - 1) * req.PerPage,
+	})
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Paginated(w, r, users, response.Page{Page: req.Page, PerPage: req.PerPage, Total: total}, response.CSV)
+}
+
+// HandleGet godoc
+// @Summary      Пользователь
+// @Description  Нужно разрешение read на users:<id>
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор пользователя"
+// @Success      200  {object}  domain.User
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem  "permission_denied"
+// @Failure      404  {object}  apperrors.Problem  "user_not_found"
+// @Router       /api/v1/users/{id} [get]
+func (c *userController) HandleGet(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[UserRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	user, err := c.userUsecase.Get(r.Context(), req.ID)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.JSON(w, r, http.StatusOK, user)
+}
+
+// HandleCreate godoc
+// @Summary      Создать пользователя
+// @Description  Email приводится к нижнему регистру и уникален. Нужно разрешение create на users
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        user  body  CreateUserRequest  true  "Пользователь"
+// @Success      201  {object}  domain.User
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem  "permission_denied"
+// @Failure      409  {object}  apperrors.Problem  "user_email_taken"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/users [post]
+func (c *userController) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[CreateUserRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	user, err := c.userUsecase.Create(r.Context(), req.Name, req.Email)
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.Created(w, r, fmt.Sprintf("/api/v1/users/%d", user.ID), user)
+}
+
+// HandleUpdate godoc
+// @Summary      Изменить пользователя
+// @Description  Меняются только переданные поля. Нужно разрешение update на users:<id>
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id    path  int                true  "Идентификатор пользователя"
+// @Param        user  body  UpdateUserRequest  true  "Изменяемые поля"
+// @Success      200  {object}  domain.User
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem  "permission_denied"
+// @Failure      404  {object}  apperrors.Problem  "user_not_found"
+// @Failure      409  {object}  apperrors.Problem  "user_email_taken"
+// @Failure      422  {object}  apperrors.Problem
+// @Router       /api/v1/users/{id} [patch]
+func (c *userController) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[UpdateUserRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	user, err := c.userUsecase.Update(r.Context(), req.ID, domain.UserPatch{Name: req.Name, Email: req.Email})
+	if err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.JSON(w, r, http.StatusOK, user)
+}
+
+// HandleDelete godoc
+// @Summary      Удалить пользователя
+// @Description  Нужно разрешение delete на users:<id>
+// @Tags         users
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path  int  true  "Идентификатор пользователя"
+// @Success      204
+// @Failure      401  {object}  apperrors.Problem
+// @Failure      403  {object}  apperrors.Problem  "permission_denied"
+// @Failure      404  {object}  apperrors.Problem  "user_not_found"
+// @Router       /api/v1/users/{id} [delete]
+func (c *userController) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	req, err := request.Bind[UserRequest](r)
+	if err != nil {
+		apperrors.Write(w, r, err)
+		return
+	}
+	if err := c.userUsecase.Delete(r.Context(), req.ID); err != nil {
+		c.fail(w, r, err)
+		return
+	}
+	response.NoContent(w)
+}
+
+// fail отвечает ошибкой use case; внутренние ошибки пишет в журнал
+func (c *userController) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if apperrors.As(err).Kind == apperrors.KindInternal {
+		c.logger.Error("User request failed", zap.String("path", r.URL.Path), zap.Error(err))
+	}
+	apperrors.Write(w, r, err)
+}
@@ -0,0 +1,140 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/container"
+	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// userRouter - маршруты контроллера users поверх use case и сервиса из контейнера
+// с моком репозитория; права и транзакции - моки. Контроллеры в контейнере доступны
+// только группой всех модулей, поэтому контроллер создаётся напрямую.
+func userRouter(t *testing.T, repo interfaces.UserRepository) http.Handler {
+	uc := container.MustResolve[interfaces.UserUsecase](t,
+		container.WithOverride(func() interfaces.UserRepository { return repo }),
+		container.WithOverride(func() interfaces.Authorizer { return &interfaces.MockAuthorizer{} }),
+		container.WithOverride(func() interfaces.TransactionManager { return &interfaces.MockTransactionManager{} }),
+	)
+	r := chi.NewRouter()
+	controllers.NewUserController(uc, zap.NewNop()).RegisterRoutes(r)
+	return r
+}
+
+// serve выполняет запрос аутентифицированного пользователя
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Kind: auth.KindUser, Subject: "1"}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUserController_ListPagination(t *testing.T) {
+	var filter domain.UserFilter
+	h := userRouter(t, &interfaces.MockUserRepository{
+		ListFunc: func(ctx context.Context, f domain.UserFilter) ([]domain.User, int64, error) {
+			filter = f
+			return []domain.User{{ID: 3}, {ID: 4}}, 5, nil
+		},
+	})
+
+	rec := serve(h, http.MethodGet, "/api/v1/users?page=2&per_page=2&sort=-name", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if filter.Limit != 2 || filter.Offset != 2 || filter.Sort != "-name" {
+		t.Fatalf("filter = %+v, want limit 2, offset 2, sort -name", filter)
+	}
+	if got := rec.Header().Get("X-Total-Count"); got != "5" {
+		t.Fatalf("X-Total-Count = %q, want 5", got)
+	}
+	link := rec.Header().Get("Link")
+	for _, want := range []string{
+		`</api/v1/users?page=1&per_page=2&sort=-name>; rel="first"`,
+		`</api/v1/users?page=1&per_page=2&sort=-name>; rel="prev"`,
+		`</api/v1/users?page=3&per_page=2&sort=-name>; rel="next"`,
+		`</api/v1/users?page=3&per_page=2&sort=-name>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Fatalf("Link = %q, missing %s", link, want)
+		}
+	}
+
+	var body struct {
+		Items      []domain.User `json:"items"`
+		Pagination struct {
+			Page       int   `json:"page"`
+			Total      int64 `json:"total"`
+			TotalPages int   `json:"total_pages"`
+		} `json:"pagination"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 || body.Pagination.Page != 2 || body.Pagination.Total != 5 || body.Pagination.TotalPages != 3 {
+		t.Fatalf("unexpected page %+v", body)
+	}
+}
+
+func TestUserController_ListDefaults(t *testing.T) {
+	var filter domain.UserFilter
+	h := userRouter(t, &interfaces.MockUserRepository{
+		ListFunc: func(ctx context.Context, f domain.UserFilter) ([]domain.User, int64, error) {
+			filter = f
+			return nil, 0, nil
+		},
+	})
+
+	rec := serve(h, http.MethodGet, "/api/v1/users", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if filter.Limit != 20 || filter.Offset != 0 {
+		t.Fatalf("filter = %+v, want default page", filter)
+	}
+	if rec.Header().Get("X-Total-Count") != "0" || rec.Header().Get("Link") != "" {
+		t.Fatalf("headers = %v, want X-Total-Count 0 without Link", rec.Header())
+	}
+
+	// Явно переданный ноль проверяется, а не заменяется значением по умолчанию
+	if rec := serve(h, http.MethodGet, "/api/v1/users?per_page=0", ""); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("per_page=0: status %d, want 422", rec.Code)
+	}
+}
+
+func TestUserController_EmptyPatch(t *testing.T) {
+	updated := false
+	h := userRouter(t, &interfaces.MockUserRepository{
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			updated = true
+			return nil
+		},
+	})
+
+	rec := serve(h, http.MethodPatch, "/api/v1/users/42", "{}")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, want 422: %s", rec.Code, rec.Body)
+	}
+	if updated {
+		t.Fatal("empty patch reached the repository")
+	}
+
+	rec = serve(h, http.MethodPatch, "/api/v1/users/42", `{"name": "New"}`)
+	if rec.Code != http.StatusOK || !updated {
+		t.Fatalf("status %d, updated %v; want 200 with update", rec.Code, updated)
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/lib/pq"
+)
+
+// APIKey - ключ доступа для сервисов и фоновых задач (схема Authorization: ApiKey).
+// Хранится только хеш ключа; Prefix - открытое начало ключа для поиска в списке.
+type APIKey struct {
+	ID     int64  `db:"id" json:"id" example:"17"`
+	Name   string `db:"name" json:"name" example:"billing-sync"`
+	Prefix string `db:"prefix" json:"prefix" example:"gbk_3f9a2b1c7d04"`
+	Hash   string `db:"hash" json:"-"`
+	// PreviousHash - хеш ключа до ротации, принимается до PreviousExpiresAt
+	PreviousHash      *string        `db:"previous_hash" json:"-"`
+	PreviousExpiresAt *time.Time     `db:"previous_expires_at" json:"previous_expires_at,omitempty"`
+	Scopes            pq.StringArray `db:"scopes" json:"scopes" swaggertype:"array,string" example:"orders:read"`
+	CreatedBy         string         `db:"created_by" json:"created_by" example:"user:42"`
+	ExpiresAt         *time.Time     `db:"expires_at" json:"expires_at,omitempty"`
+	LastUsedAt        *time.Time     `db:"last_used_at" json:"last_used_at,omitempty"`
+	RotatedAt         *time.Time     `db:"rotated_at" json:"rotated_at,omitempty"`
+	RevokedAt         *time.Time     `db:"revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+}
+
+// Active сообщает, принимается ли ключ в момент now: не отозван и не истёк
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && !k.Expired(now)
+}
+
+// Expired сообщает, истёк ли срок действия ключа к моменту now
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Accepts сообщает, принимается ли в момент now ключ с хешем hash:
+// текущий секрет или прежний до окончания периода ротации
+func (k *APIKey) Accepts(hash string, now time.Time) bool {
+	if !k.Active(now) {
+		return false
+	}
+	if hash == k.Hash {
+		return true
+	}
+	return k.PreviousHash != nil && *k.PreviousHash == hash &&
+		k.PreviousExpiresAt != nil && now.Before(*k.PreviousExpiresAt)
+}
+
+// IssuedAPIKey - созданный или перевыпущенный ключ. Key показывается только
+// в ответе на создание и ротацию, повторно получить его нельзя.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"gbk_3f9a2b1c7d04_Vb1x6l0Hq3oQ2n5sK8dYp4TzR7wE9uJc0aFgMhNiLkU"`
+}
+
+// APIKeyFilter - отбор ключей; отозванные - только с WithRevoked
+type APIKeyFilter struct {
+	Name        string
+	WithRevoked bool
+	Limit       int
+	Offset      int
+}
+
+// Ошибки API ключей
+var (
+	ErrAPIKeyNotFound = apperrors.NotFound("api key not found").WithCode("api_key_not_found")
+	ErrAPIKeyRevoked  = apperrors.Conflict("api key is revoked").WithCode("api_key_revoked")
+	ErrAPIKeyExpired  = apperrors.Conflict("api key is expired").WithCode("api_key_expired")
+	ErrAPIKeyInvalid  = apperrors.Unauthorized("invalid, expired or revoked api key").WithCode("invalid_token")
+)
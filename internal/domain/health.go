@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Probe - проба Kubernetes, для которой выполняются проверки
+type Probe string
+
+const (
+	// ProbeLiveness - процесс жив (/livez); провал - перезапуск контейнера
+	ProbeLiveness Probe = "liveness"
+	// ProbeReadiness - экземпляр готов принимать трафик (/readyz)
+	ProbeReadiness Probe = "readiness"
+	// ProbeStartup - экземпляр завершил запуск (/startupz)
+	ProbeStartup Probe = "startup"
+)
+
+// HealthStatus - состояние компонента или пробы целиком
+type HealthStatus string
+
+const (
+	HealthUp HealthStatus = "up"
+	// HealthDegraded - не работает необязательный компонент, экземпляр обслуживает запросы
+	HealthDegraded HealthStatus = "degraded"
+	// HealthDown - не работает обязательный компонент
+	HealthDown HealthStatus = "down"
+)
+
+// HealthCheckResult - результат проверки одного компонента
+type HealthCheckResult struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	Required  bool         `json:"required"`
+	LatencyMs float64      `json:"latency_ms"`
+	// Error - ошибка текущей проверки
+	Error string `json:"error,omitempty"`
+	// LastError и LastErrorAt - последняя ошибка компонента, в том числе уже исправленная
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	CheckedAt   time.Time  `json:"checked_at"`
+}
+
+// HealthReport - ответ health эндпоинта
+type HealthReport struct {
+	Probe     Probe               `json:"probe"`
+	Status    HealthStatus        `json:"status"`
+	Checks    []HealthCheckResult `json:"checks"`
+	CheckedAt time.Time           `json:"checked_at"`
+	// Cached - результаты взяты из фонового обновления, а не выполнены по запросу
+	Cached bool `json:"cached"`
+	// AgeMs - возраст самого старого результата в отчёте
+	AgeMs float64 `json:"age_ms"`
+}
+
+// HealthTransition - смена состояния проверки (up, degraded, down)
+type HealthTransition struct {
+	Name string       `json:"name"`
+	From HealthStatus `json:"from"`
+	To   HealthStatus `json:"to"`
+	// Error - ошибка проверки, вызвавшей переход (пусто при переходе в up)
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+// Role - роль RBAC: набор разрешений, который выдаётся субъектам привязками
+type Role struct {
+	ID          int64        `db:"id" json:"id" example:"1"`
+	Name        string       `db:"name" json:"name" example:"order-manager"`
+	Description string       `db:"description" json:"description" example:"Управление заказами"`
+	CreatedAt   time.Time    `db:"created_at" json:"created_at"`
+	Permissions []Permission `db:"-" json:"permissions"`
+}
+
+// Permission - разрешение роли: действие над ресурсом. "*" - любое действие
+// или ресурс, "*" в конце - префикс ("orders:*" - все ресурсы orders:...)
+type Permission struct {
+	ID       int64  `db:"id" json:"id" example:"7"`
+	RoleID   int64  `db:"role_id" json:"-"`
+	Action   string `db:"action" json:"action" example:"write"`
+	Resource string `db:"resource" json:"resource" example:"orders:*"`
+}
+
+// Allows сообщает, покрывает ли разрешение действие action над resource
+func (p Permission) Allows(action, resource string) bool {
+	return matchPattern(p.Action, action) && matchPattern(p.Resource, resource)
+}
+
+func matchPattern(pattern, value string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+	return pattern == value
+}
+
+// RoleBinding - выдача роли субъекту: пользователю (sub токена) или API ключу
+type RoleBinding struct {
+	ID          int64     `db:"id" json:"id" example:"3"`
+	SubjectKind string    `db:"subject_kind" json:"subject_kind" example:"user"`
+	Subject     string    `db:"subject" json:"subject" example:"42"`
+	RoleID      int64     `db:"role_id" json:"-"`
+	Role        string    `db:"role" json:"role" example:"order-manager"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// RoleBindingFilter - отбор привязок; пустые поля не ограничивают выборку
+type RoleBindingFilter struct {
+	SubjectKind string
+	Subject     string
+	Role        string
+	Limit       int
+	Offset      int
+}
+
+// Ошибки RBAC
+var (
+	ErrRoleNotFound        = apperrors.NotFound("role not found").WithCode("role_not_found")
+	ErrRoleExists          = apperrors.Conflict("role already exists").WithCode("role_exists")
+	ErrPermissionNotFound  = apperrors.NotFound("permission not found").WithCode("permission_not_found")
+	ErrPermissionExists    = apperrors.Conflict("role already has this permission").WithCode("permission_exists")
+	ErrRoleBindingNotFound = apperrors.NotFound("role binding not found").WithCode("role_binding_not_found")
+	ErrRoleBindingExists   = apperrors.Conflict("subject already has this role").WithCode("role_binding_exists")
+	// ErrPermissionDenied - у субъекта нет разрешения на действие (Authorize)
+	ErrPermissionDenied = apperrors.Forbidden("permission denied").WithCode("permission_denied")
+)
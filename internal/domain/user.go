@@ -1,12 +1,59 @@
 package domain
 
-import "time"
+import (
+	"time"
 
-// User - пример доменной модели пользователя
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+// User - пользователь (модуль users)
 type User struct {
-	ID        int64     `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	Email     string    `db:"email" json:"email"`
+	ID        int64     `db:"id" json:"id" example:"42"`
+	Name      string    `db:"name" json:"name" example:"Иван Петров"`
+	Email     string    `db:"email" json:"email" example:"ivan@example.com"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// UserPatch - частичное изменение пользователя: nil поля не меняются
+type UserPatch struct {
+	Name  *string
+	Email *string
+}
+
+// Apply переносит заданные поля patch в u
+func (p UserPatch) Apply(u *User) {
+	if p.Name != nil {
+		u.Name = *p.Name
+	}
+	if p.Email != nil {
+		u.Email = *p.Email
+	}
+}
+
+// Поля сортировки списка пользователей; "-" перед полем - по убыванию
+const (
+	UserSortID        = "id"
+	UserSortName      = "name"
+	UserSortCreatedAt = "created_at"
+)
+
+// UserFilter - отбор пользователей; пустые поля не ограничивают выборку
+type UserFilter struct {
+	// Query - подстрока имени или email без учёта регистра
+	Query string
+	Email string
+	// CreatedFrom и CreatedTo - полуинтервал [from, to) по created_at
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Sort - поле UserSort*, с "-" - по убыванию; пустое - по id
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// Ошибки пользователей
+var (
+	ErrUserNotFound   = apperrors.NotFound("user not found").WithCode("user_not_found")
+	ErrUserEmailTaken = apperrors.Conflict("email is already taken").WithCode("user_email_taken")
+)
@@ -0,0 +1,69 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// Disk - имя проверки свободного места на диске
+const Disk = "disk"
+
+// PostgresCheck проверяет подключение к PostgreSQL для readiness и startup проб
+// функцией ping (обычно HealthcheckRepository.Ping) и обновляет состояние
+// зависимости в status
+func PostgresCheck(ping func(ctx context.Context) error, required bool, status interfaces.DependencyStatus) interfaces.HealthCheck {
+	return interfaces.HealthCheck{
+		Name:     Postgres,
+		Probes:   []domain.Probe{domain.ProbeReadiness, domain.ProbeStartup},
+		Required: required,
+		Check: func(ctx context.Context) error {
+			err := ping(ctx)
+			status.Set(Postgres, err)
+			return err
+		},
+	}
+}
+
+// RabbitMQCheck проверяет, что соединение с RabbitMQ открыто и на нём
+// открывается канал (readiness проба)
+func RabbitMQCheck(r *RabbitMQConn, required bool) interfaces.HealthCheck {
+	return interfaces.HealthCheck{
+		Name:     RabbitMQ,
+		Probes:   []domain.Probe{domain.ProbeReadiness},
+		Required: required,
+		Check: func(ctx context.Context) error {
+			conn, err := r.Conn()
+			if err != nil {
+				return err
+			}
+			ch, err := conn.Channel()
+			if err != nil {
+				return fmt.Errorf("open channel: %w", err)
+			}
+			return ch.Close()
+		},
+	}
+}
+
+// DiskCheck проверяет, что на файловой системе path свободно не меньше minFreeMB мегабайт.
+// Необязательная проверка readiness пробы: нехватка места - повод для алерта, а не для снятия трафика.
+// Свободное место определяется только на unix системах (DiskCheckSupported).
+func DiskCheck(path string, minFreeMB int) interfaces.HealthCheck {
+	return interfaces.HealthCheck{
+		Name:   Disk,
+		Probes: []domain.Probe{domain.ProbeReadiness},
+		Check: func(ctx context.Context) error {
+			freeMB, err := freeDiskMB(path)
+			if err != nil {
+				return err
+			}
+			if freeMB < uint64(minFreeMB) {
+				return fmt.Errorf("%s: %d MB free, want at least %d MB", path, freeMB, minFreeMB)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package infra
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// DiskCheckSupported - на этой платформе проверка диска не регистрируется
+const DiskCheckSupported = false
+
+func freeDiskMB(path string) (uint64, error) {
+	return 0, fmt.Errorf("disk check is not supported on %s", runtime.GOOS)
+}
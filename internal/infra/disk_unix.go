@@ -0,0 +1,20 @@
+//go:build unix
+
+package infra
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// DiskCheckSupported - свободное место определяется через statfs
+const DiskCheckSupported = true
+
+// freeDiskMB возвращает свободное для непривилегированного пользователя место на файловой системе path
+func freeDiskMB(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	return stat.Bavail * uint64(stat.Bsize) / (1 << 20), nil
+}
@@ -0,0 +1,62 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// ConnectPostgres проверяет подключение к PostgreSQL с таймаутом connectTimeout
+// (0 - без ограничения). Для обязательной зависимости ошибка возвращается сразу;
+// для необязательной - зависимость помечается недоступной, а проверка повторяется
+// в фоне с интервалом policy.ReconnectInterval до успеха или отмены ctx.
+// Пул database/sql переподключается сам, поэтому после успешной проверки
+// дальнейшие разрывы видны только health эндпоинтам.
+func ConnectPostgres(ctx context.Context, db *sqlx.DB, connectTimeout time.Duration, policy Policy, status interfaces.DependencyStatus, logger *zap.Logger) error {
+	status.Register(Postgres, policy.Required)
+
+	err := pingPostgres(ctx, db, connectTimeout)
+	status.Set(Postgres, err)
+	if err == nil {
+		return nil
+	}
+	if policy.Required {
+		return fmt.Errorf("postgres: %w", err)
+	}
+
+	logger.Warn("PostgreSQL unavailable, starting in degraded mode",
+		zap.Error(err),
+		zap.Duration("reconnectInterval", policy.ReconnectInterval),
+	)
+	go func() {
+		ticker := time.NewTicker(policy.ReconnectInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := pingPostgres(ctx, db, connectTimeout)
+			status.Set(Postgres, err)
+			if err == nil {
+				logger.Info("PostgreSQL connected")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func pingPostgres(ctx context.Context, db *sqlx.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return db.PingContext(ctx)
+}
@@ -0,0 +1,190 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+// dialTimeout - таймаут установки TCP соединения с RabbitMQ
+const dialTimeout = 10 * time.Second
+
+// ErrNotConfigured - rabbitmq.url не задан (например, у крон скрипта без очередей)
+var ErrNotConfigured = errors.New("rabbitmq is not configured")
+
+// RabbitMQConn - подключение к RabbitMQ с фоновым переподключением.
+// В отличие от rabbitmq.NewRabbitMQConnection из toolbox, не завершает процесс
+// при ошибке: обязательная зависимость возвращает ошибку из NewRabbitMQConn,
+// необязательная подключается в фоне. Реализует interfaces.Publisher.
+type RabbitMQConn struct {
+	url    string
+	policy Policy
+	status interfaces.DependencyStatus
+	logger *zap.Logger
+
+	mu   sync.RWMutex
+	conn *amqp.Connection
+
+	// pubMu защищает канал публикации, открытый на подключении pubConn
+	pubMu   sync.Mutex
+	pubCh   *amqp.Channel
+	pubConn *amqp.Connection
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRabbitMQConn подключается к RabbitMQ и запускает фоновое наблюдение за соединением.
+// Пустой url - RabbitMQ не используется: соединение не открывается, Conn возвращает ErrNotConfigured.
+func NewRabbitMQConn(url string, policy Policy, status interfaces.DependencyStatus, logger *zap.Logger) (*RabbitMQConn, error) {
+	r := &RabbitMQConn{
+		url:    url,
+		policy: policy,
+		status: status,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	if url == "" {
+		close(r.done)
+		return r, nil
+	}
+
+	status.Register(RabbitMQ, policy.Required)
+	if err := r.dial(); err != nil {
+		if policy.Required {
+			return nil, fmt.Errorf("rabbitmq: %w", err)
+		}
+		logger.Warn("RabbitMQ unavailable, starting in degraded mode",
+			zap.Error(err),
+			zap.Duration("reconnectInterval", policy.ReconnectInterval),
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.run(ctx)
+	return r, nil
+}
+
+// Conn возвращает текущее подключение. Ссылка на него действительна до разрыва
+// соединения; после переподключения нужно снова вызвать Conn.
+func (r *RabbitMQConn) Conn() (*amqp.Connection, error) {
+	if r.url == "" {
+		return nil, ErrNotConfigured
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.conn == nil {
+		return nil, fmt.Errorf("rabbitmq: %w", ErrUnavailable)
+	}
+	return r.conn, nil
+}
+
+// Publish публикует сообщение с задержкой delay (заголовок x-delay плагина
+// delayed message exchange). Канал публикации открывается заново после
+// переподключения; пока RabbitMQ недоступен, возвращается ErrUnavailable.
+func (r *RabbitMQConn) Publish(message []byte, delay time.Duration, exchange string, key string) error {
+	conn, err := r.Conn()
+	if err != nil {
+		return err
+	}
+
+	r.pubMu.Lock()
+	defer r.pubMu.Unlock()
+
+	if r.pubCh == nil || r.pubConn != conn {
+		ch, err := conn.Channel()
+		if err != nil {
+			return fmt.Errorf("rabbitmq: open channel: %w", err)
+		}
+		r.pubCh, r.pubConn = ch, conn
+	}
+
+	err = r.pubCh.Publish(exchange, key, false, false, amqp.Publishing{
+		Body:    message,
+		Headers: amqp.Table{"x-delay": int32(delay.Milliseconds())},
+	})
+	if err != nil {
+		// Канал закрывается брокером после ошибки - следующая публикация откроет новый
+		r.pubCh = nil
+	}
+	return err
+}
+
+// Close останавливает переподключение и закрывает соединение
+func (r *RabbitMQConn) Close() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	<-r.done
+
+	r.mu.Lock()
+	conn := r.conn
+	r.conn = nil
+	r.mu.Unlock()
+
+	if conn == nil || conn.IsClosed() {
+		return nil
+	}
+	return conn.Close()
+}
+
+func (r *RabbitMQConn) dial() error {
+	conn, err := amqp.DialConfig(r.url, amqp.Config{Dial: amqp.DefaultDial(dialTimeout)})
+	r.status.Set(RabbitMQ, err)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.conn = conn
+	r.mu.Unlock()
+	return nil
+}
+
+// run ждёт разрыва соединения, помечает зависимость недоступной
+// и переподключается с интервалом policy.ReconnectInterval до успеха или Close
+func (r *RabbitMQConn) run(ctx context.Context) {
+	defer close(r.done)
+
+	for {
+		r.mu.RLock()
+		conn := r.conn
+		r.mu.RUnlock()
+
+		if conn != nil {
+			closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+			select {
+			case <-ctx.Done():
+				return
+			case amqpErr := <-closed:
+				var err error = ErrUnavailable
+				if amqpErr != nil {
+					err = amqpErr
+				}
+				r.mu.Lock()
+				r.conn = nil
+				r.mu.Unlock()
+				r.status.Set(RabbitMQ, err)
+				r.logger.Warn("RabbitMQ connection lost, reconnecting", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.policy.ReconnectInterval):
+		}
+
+		if err := r.dial(); err != nil {
+			r.logger.Debug("RabbitMQ reconnect failed", zap.Error(err))
+			continue
+		}
+		r.logger.Info("RabbitMQ connected")
+	}
+}
@@ -0,0 +1,145 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+// defaultResubscribeInterval - пауза между попытками подписки, если reconnect_interval не задан
+const defaultResubscribeInterval = time.Second
+
+// RabbitMQConsumer читает очередь через текущее подключение RabbitMQConn.
+// В отличие от rabbitmq.RabbitMQConsumer из toolbox, не привязан к соединению,
+// с которым был создан: после переподключения канал открывается заново.
+type RabbitMQConsumer struct {
+	conn     *RabbitMQConn
+	queue    string
+	prefetch int
+	logger   *zap.Logger
+
+	mu sync.Mutex
+	ch *amqp.Channel
+}
+
+// NewRabbitMQConsumer создаёт consumer durable очереди queue с заданным prefetch (0 - без ограничения)
+func NewRabbitMQConsumer(conn *RabbitMQConn, queue string, prefetch int, logger *zap.Logger) *RabbitMQConsumer {
+	return &RabbitMQConsumer{
+		conn:     conn,
+		queue:    queue,
+		prefetch: prefetch,
+		logger:   logger,
+	}
+}
+
+// Consume возвращает поток сообщений очереди. Пока RabbitMQ недоступен, подписка
+// повторяется с интервалом переподключения; поток закрывается после отмены ctx.
+// Сообщения, полученные до разрыва, подтвердить уже нельзя - брокер доставит их повторно.
+func (c *RabbitMQConsumer) Consume(ctx context.Context) (<-chan amqp.Delivery, error) {
+	if c.conn.url == "" {
+		return nil, ErrNotConfigured
+	}
+
+	interval := c.conn.policy.ReconnectInterval
+	if interval <= 0 {
+		interval = defaultResubscribeInterval
+	}
+
+	out := make(chan amqp.Delivery)
+	go func() {
+		defer close(out)
+		for {
+			deliveries, err := c.subscribe()
+			if err != nil {
+				c.logger.Debug("RabbitMQ subscribe failed", zap.String("queue", c.queue), zap.Error(err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+				continue
+			}
+
+			c.logger.Info("RabbitMQ consumer subscribed", zap.String("queue", c.queue))
+			if !forward(ctx, deliveries, out) {
+				return
+			}
+			c.logger.Warn("RabbitMQ consumer channel closed, resubscribing", zap.String("queue", c.queue))
+		}
+	}()
+	return out, nil
+}
+
+// Close закрывает текущий канал подписки; соединение закрывается вместе с RabbitMQConn
+func (c *RabbitMQConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch == nil {
+		return nil
+	}
+	err := c.ch.Close()
+	c.ch = nil
+	if err == amqp.ErrClosed {
+		return nil
+	}
+	return err
+}
+
+// subscribe открывает канал на текущем подключении, объявляет очередь и подписывается на неё
+func (c *RabbitMQConsumer) subscribe() (<-chan amqp.Delivery, error) {
+	conn, err := c.conn.Conn()
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq: open channel: %w", err)
+	}
+	if err := ch.Qos(c.prefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("rabbitmq: set prefetch: %w", err)
+	}
+	if _, err := ch.QueueDeclare(c.queue, true, false, false, false, nil); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("rabbitmq: declare queue %s: %w", c.queue, err)
+	}
+	deliveries, err := ch.Consume(c.queue, "", false, false, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("rabbitmq: consume queue %s: %w", c.queue, err)
+	}
+
+	c.mu.Lock()
+	prev := c.ch
+	c.ch = ch
+	c.mu.Unlock()
+	if prev != nil {
+		prev.Close()
+	}
+	return deliveries, nil
+}
+
+// forward пересылает сообщения канала в out. Возвращает false после отмены ctx
+// и true, если канал закрыт вместе с соединением
+func forward(ctx context.Context, deliveries <-chan amqp.Delivery, out chan<- amqp.Delivery) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case msg, ok := <-deliveries:
+			if !ok {
+				return true
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRabbitMQConsumerNotConfigured(t *testing.T) {
+	consumer := NewRabbitMQConsumer(&RabbitMQConn{}, "tasks", 0, zap.NewNop())
+	if _, err := consumer.Consume(context.Background()); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("Consume error = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestRabbitMQConsumerWaitsForConnection(t *testing.T) {
+	// Подключения нет - consumer повторяет подписку, пока не отменён ctx
+	conn := &RabbitMQConn{url: "amqp://127.0.0.1:1/", policy: Policy{ReconnectInterval: 10 * time.Millisecond}}
+	consumer := NewRabbitMQConsumer(conn, "tasks", 0, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	messages, err := consumer.Consume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-messages:
+		t.Fatal("messages closed before cancel")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case _, ok := <-messages:
+		if ok {
+			t.Fatal("unexpected message")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("messages not closed after cancel")
+	}
+	if err := consumer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package infra - подключение к внешним зависимостям (PostgreSQL, RabbitMQ)
+// с политикой required/optional: необязательная зависимость не мешает старту,
+// а переподключается в фоне, пока бинарник работает в деградированном режиме.
+package infra
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// Имена зависимостей в interfaces.DependencyStatus
+const (
+	Postgres = "postgres"
+	RabbitMQ = "rabbitmq"
+)
+
+// ErrUnavailable - зависимость сейчас недоступна (идёт переподключение)
+var ErrUnavailable = errors.New("dependency unavailable")
+
+// Policy - политика подключения к зависимости
+type Policy struct {
+	// Required - ошибка первого подключения возвращается вызывающему
+	Required bool
+	// ReconnectInterval - период повторных попыток подключения
+	ReconnectInterval time.Duration
+}
+
+type status struct {
+	mu    sync.RWMutex
+	deps  map[string]*interfaces.DependencyState
+	order []string
+}
+
+func NewStatus() interfaces.DependencyStatus {
+	return &status{deps: make(map[string]*interfaces.DependencyState)}
+}
+
+// Register добавляет зависимость; до первого Set она считается недоступной
+func (s *status) Register(name string, required bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.deps[name]; ok {
+		d.Required = required
+		return
+	}
+	s.deps[name] = &interfaces.DependencyState{
+		Name:     name,
+		Required: required,
+		Error:    "not connected",
+		Since:    time.Now(),
+	}
+	s.order = append(s.order, name)
+}
+
+// Set обновляет состояние зависимости: nil - доступна, иначе недоступна с ошибкой err
+func (s *status) Set(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deps[name]
+	if !ok {
+		return
+	}
+	up := err == nil
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	if d.Up != up {
+		d.Since = time.Now()
+	}
+	d.Up = up
+	d.Error = msg
+}
+
+func (s *status) IsUp(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deps[name]
+	return ok && d.Up
+}
+
+// Dependencies возвращает копию состояний в порядке регистрации
+func (s *status) Dependencies() []interfaces.DependencyState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	deps := make([]interfaces.DependencyState, 0, len(s.order))
+	for _, name := range s.order {
+		deps = append(deps, *s.deps[name])
+	}
+	return deps
+}
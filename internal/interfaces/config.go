@@ -1,9 +1,150 @@
 package interfaces
 
-type ConfigServer interface {
+import (
+	"net"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/ratelimit"
+)
+
+// ConfigDB - настройки подключения к PostgreSQL (общий блок db)
+type ConfigDB interface {
 	GetDBDsn() string
 	GetDBMaxOpenConns() int
 	GetDBMaxIdleConns() int
-	GetRunAddr() string
+	GetDBConnMaxLifetime() time.Duration
+	GetDBConnMaxIdleTime() time.Duration
+	GetDBConnectTimeout() time.Duration
+	GetDBStatementTimeout() time.Duration
+	GetDBRequired() bool
+	GetDBReconnectInterval() time.Duration
+}
+
+// ConfigRabbitMQ - настройки подключения к RabbitMQ (общий блок rabbitmq)
+type ConfigRabbitMQ interface {
 	GetRabbitMQURL() string
+	GetRabbitMQRequired() bool
+	GetRabbitMQReconnectInterval() time.Duration
+}
+
+// ConfigLog - настройки логирования (общий блок log)
+type ConfigLog interface {
+	GetLogLevel() string
+}
+
+// ConfigHealth - настройки проверок здоровья (общий блок health)
+type ConfigHealth interface {
+	GetHealthCheckTimeout() time.Duration
+	GetHealthDiskPath() string
+	GetHealthDiskMinFreeMB() int
+	GetHealthRefreshInterval() time.Duration
+	GetHealthMaxAgeIntervals() int
+	GetHealthFreshInterval() time.Duration
+}
+
+// ConfigShutdown - дедлайны этапов graceful shutdown (общий блок shutdown)
+type ConfigShutdown interface {
+	GetShutdownDrainPeriod() time.Duration
+	GetShutdownHTTPTimeout() time.Duration
+	GetShutdownComponentsTimeout() time.Duration
+	GetShutdownConnectionsTimeout() time.Duration
+}
+
+// ConfigModules - включение и отключение модулей (общий блок modules)
+type ConfigModules interface {
+	GetDisabledModules() []string
+}
+
+// ConfigCommon - блоки, общие для всех бинарников.
+// Инфраструктурные провайдеры контейнера зависят только от него.
+type ConfigCommon interface {
+	ConfigDB
+	ConfigRabbitMQ
+	ConfigLog
+	ConfigHealth
+	ConfigShutdown
+	ConfigModules
+	ConfigRBAC
+}
+
+// ConfigRBAC - настройки авторизации (общий блок rbac)
+type ConfigRBAC interface {
+	GetRBACCacheTTL() time.Duration
+	GetRBACTrustTokenRoles() bool
+}
+
+// CORSPolicy - политика CORS для middleware.CORS
+type CORSPolicy struct {
+	// AllowedOrigins - разрешённые источники: точные ("https://app.example.com"),
+	// с одной звёздочкой ("https://*.example.com") или "*" - любой
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowedHeaders - заголовки запроса, "*" - любые
+	AllowedHeaders []string
+	// ExposedHeaders - заголовки ответа, доступные скрипту
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge - время кеширования preflight ответа браузером, 0 - не отправлять
+	MaxAge time.Duration
+}
+
+// ConfigMiddleware - включение и настройки HTTP middleware (блок middleware сервера)
+type ConfigMiddleware interface {
+	GetRequestIDEnabled() bool
+	GetRequestIDHeader() string
+	GetRecoveryEnabled() bool
+	GetAccessLogEnabled() bool
+	GetAccessLogSkipPaths() []string
+	GetRealIPEnabled() bool
+	GetRealIPTrustedProxies() []*net.IPNet
+	GetTimeoutEnabled() bool
+	GetTimeoutDefault() time.Duration
+	GetTimeoutRoutes() map[string]time.Duration
+	GetBodyLimitEnabled() bool
+	GetBodyLimitMaxBytes() int64
+	GetBodyLimitRoutes() map[string]int64
+	GetCORSEnabled() bool
+	GetCORSPolicy() CORSPolicy
+	GetRateLimitEnabled() bool
+	GetRateLimitAlgorithm() string
+	GetRateLimitStore() string
+	GetRateLimitKeys() []string
+	GetRateLimitDefault() ratelimit.Limit
+	GetRateLimitRoutes() map[string]ratelimit.Limit
+	GetRateLimitAuth() ratelimit.Limit
+}
+
+// ConfigAuth - аутентификация запросов (блок auth сервера)
+type ConfigAuth interface {
+	GetJWTEnabled() bool
+	GetJWTConfig() auth.JWTConfig
+	GetAPIKeyEnabled() bool
+	GetAPIKeyRotationGracePeriod() time.Duration
+}
+
+// ConfigServer - конфигурация HTTP сервера (cmd/server)
+type ConfigServer interface {
+	ConfigCommon
+	ConfigMiddleware
+	ConfigAuth
+	GetRunAddr() string
+	GetAppEnv() string
+	IsDevelopment() bool
+	GetConfigWatchInterval() time.Duration
+}
+
+// ConfigConsumer - конфигурация RabbitMQ consumer (cmd/crons/rabbitmq_consumer)
+type ConfigConsumer interface {
+	ConfigCommon
+	GetConsumerQueue() string
+	GetConsumerPrefetch() int
+}
+
+// ConfigCron - конфигурация крон скриптов (cmd/crons/*)
+type ConfigCron interface {
+	ConfigCommon
+	GetCronSchedule() time.Duration
+	GetCronTimeout() time.Duration
+	GetCronJob() string
 }
@@ -1,8 +1,57 @@
 package interfaces
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Controller - контроллер, который сам объявляет свои маршруты. Роутер вызывает
+// RegisterRoutes в отдельной группе chi: middleware из r.Use действуют только
+// на маршруты этого контроллера, префиксы (например, /api/v1) задаёт r.Route.
+type Controller interface {
+	RegisterRoutes(r chi.Router)
+}
 
 // HealthcheckController интерфейс контроллера
 type HealthcheckController interface {
+	Controller
 	HandlePing(w http.ResponseWriter, r *http.Request)
+	HandleLivez(w http.ResponseWriter, r *http.Request)
+	HandleReadyz(w http.ResponseWriter, r *http.Request)
+	HandleStartupz(w http.ResponseWriter, r *http.Request)
+}
+
+// RBACController интерфейс контроллера администрирования RBAC
+type RBACController interface {
+	Controller
+	HandleListRoles(w http.ResponseWriter, r *http.Request)
+	HandleGetRole(w http.ResponseWriter, r *http.Request)
+	HandleCreateRole(w http.ResponseWriter, r *http.Request)
+	HandleDeleteRole(w http.ResponseWriter, r *http.Request)
+	HandleAddPermission(w http.ResponseWriter, r *http.Request)
+	HandleRemovePermission(w http.ResponseWriter, r *http.Request)
+	HandleListBindings(w http.ResponseWriter, r *http.Request)
+	HandleCreateBinding(w http.ResponseWriter, r *http.Request)
+	HandleDeleteBinding(w http.ResponseWriter, r *http.Request)
+}
+
+// APIKeyController интерфейс контроллера API ключей
+type APIKeyController interface {
+	Controller
+	HandleList(w http.ResponseWriter, r *http.Request)
+	HandleGet(w http.ResponseWriter, r *http.Request)
+	HandleCreate(w http.ResponseWriter, r *http.Request)
+	HandleRotate(w http.ResponseWriter, r *http.Request)
+	HandleRevoke(w http.ResponseWriter, r *http.Request)
+}
+
+// UserController интерфейс контроллера пользователей
+type UserController interface {
+	Controller
+	HandleList(w http.ResponseWriter, r *http.Request)
+	HandleGet(w http.ResponseWriter, r *http.Request)
+	HandleCreate(w http.ResponseWriter, r *http.Request)
+	HandleUpdate(w http.ResponseWriter, r *http.Request)
+	HandleDelete(w http.ResponseWriter, r *http.Request)
 }
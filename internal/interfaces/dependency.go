@@ -0,0 +1,31 @@
+package interfaces
+
+import "time"
+
+// DependencyState - состояние внешней зависимости (PostgreSQL, RabbitMQ)
+type DependencyState struct {
+	Name string `json:"name"`
+	// Required - без зависимости бинарник не работает (db.required, rabbitmq.required)
+	Required bool `json:"required"`
+	Up       bool `json:"up"`
+	// Error - последняя ошибка подключения, пусто для доступной зависимости
+	Error string `json:"error,omitempty"`
+	// Since - время последнего изменения состояния
+	Since time.Time `json:"since"`
+}
+
+// DependencyStatus - реестр состояний внешних зависимостей.
+// Провайдеры инфраструктуры обновляют его при подключении и разрыве соединения,
+// health эндпоинты читают его, чтобы сообщить о деградированном режиме.
+type DependencyStatus interface {
+	Register(name string, required bool)
+	Set(name string, err error)
+	IsUp(name string) bool
+	Dependencies() []DependencyState
+}
+
+// Publisher публикует сообщения в RabbitMQ через текущее подключение
+// и продолжает работать после переподключения
+type Publisher interface {
+	Publish(message []byte, delay time.Duration, exchange string, key string) error
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+)
+
+// HealthCheck - проверка компонента для health эндпоинтов.
+// Инфраструктура и модули добавляют проверки в value group module.GroupHealthChecks.
+type HealthCheck struct {
+	Name string
+	// Probes - пробы, в которых участвует проверка
+	Probes []domain.Probe
+	// Required - ошибка переводит пробу в down (503), иначе в degraded
+	Required bool
+	// Timeout - таймаут проверки, 0 - health.check_timeout
+	Timeout time.Duration
+	Check   func(ctx context.Context) error
+}
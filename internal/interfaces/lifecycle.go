@@ -15,4 +15,32 @@ type Shutdowner interface {
 // LoggerCloser интерфейс для логгеров (zap использует Sync вместо Close)
 type LoggerCloser interface {
 	Sync() error
-}
\ No newline at end of file
+}
+
+// HookStage - этап остановки, к которому относится хук
+type HookStage int
+
+const (
+	// StageComponent - компоненты приложения (consumers, producers, фоновые задачи),
+	// останавливаются первыми
+	StageComponent HookStage = iota
+	// StageConnection - подключения к PostgreSQL и RabbitMQ и логгер, закрываются последними
+	StageConnection
+)
+
+// Hook - хуки запуска и остановки компонента. Любой из хуков может быть nil.
+type Hook struct {
+	// Name - имя компонента в ошибках и отчёте о зависших компонентах
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+	// Stage - этап остановки, по умолчанию StageComponent
+	Stage HookStage
+}
+
+// Lifecycle интерфейс регистрации хуков жизненного цикла.
+// Провайдеры контейнера добавляют хуки сразу после создания компонента,
+// поэтому порядок регистрации совпадает с порядком зависимостей.
+type Lifecycle interface {
+	Append(hook Hook)
+}
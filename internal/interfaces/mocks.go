@@ -2,6 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
 	"net/http"
 )
 
@@ -9,7 +12,8 @@ import (
 
 // MockHealthcheckRepository - мок репозитория для тестирования
 type MockHealthcheckRepository struct {
-	PingFunc func(ctx context.Context) error
+	PingFunc             func(ctx context.Context) error
+	MigrationVersionFunc func(ctx context.Context, table string) (uint, bool, error)
 }
 
 func (m *MockHealthcheckRepository) Ping(ctx context.Context) error {
@@ -19,21 +23,53 @@ func (m *MockHealthcheckRepository) Ping(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockHealthcheckRepository) MigrationVersion(ctx context.Context, table string) (uint, bool, error) {
+	if m.MigrationVersionFunc != nil {
+		return m.MigrationVersionFunc(ctx, table)
+	}
+	return 1, false, nil
+}
+
 // MockHealthcheckService - мок сервиса для тестирования
 type MockHealthcheckService struct {
-	CheckFunc func(ctx context.Context) (map[string]interface{}, error)
+	CheckFunc      func(ctx context.Context, probe domain.Probe) domain.HealthReport
+	CheckFreshFunc func(ctx context.Context, probe domain.Probe) domain.HealthReport
 }
 
-func (m *MockHealthcheckService) Check(ctx context.Context) (map[string]interface{}, error) {
+func (m *MockHealthcheckService) Check(ctx context.Context, probe domain.Probe) domain.HealthReport {
 	if m.CheckFunc != nil {
-		return m.CheckFunc(ctx)
+		return m.CheckFunc(ctx, probe)
 	}
-	return map[string]interface{}{"status": "ok"}, nil
+	return domain.HealthReport{Probe: probe, Status: domain.HealthUp, Checks: []domain.HealthCheckResult{}}
 }
 
+// CheckFresh без CheckFreshFunc делегирует в Check
+func (m *MockHealthcheckService) CheckFresh(ctx context.Context, probe domain.Probe) domain.HealthReport {
+	if m.CheckFreshFunc != nil {
+		return m.CheckFreshFunc(ctx, probe)
+	}
+	return m.Check(ctx, probe)
+}
+
+func (m *MockHealthcheckService) Subscribe(fn func(domain.HealthTransition)) {}
+
+func (m *MockHealthcheckService) Run(ctx context.Context) {}
+
+func (m *MockHealthcheckService) Drain() {}
+
 // MockHealthcheckController - мок контроллера для тестирования
 type MockHealthcheckController struct {
-	HandlePingFunc func(w http.ResponseWriter, r *http.Request)
+	RegisterRoutesFunc func(r chi.Router)
+	HandlePingFunc     func(w http.ResponseWriter, r *http.Request)
+	HandleLivezFunc    func(w http.ResponseWriter, r *http.Request)
+	HandleReadyzFunc   func(w http.ResponseWriter, r *http.Request)
+	HandleStartupzFunc func(w http.ResponseWriter, r *http.Request)
+}
+
+func (m *MockHealthcheckController) RegisterRoutes(r chi.Router) {
+	if m.RegisterRoutesFunc != nil {
+		m.RegisterRoutesFunc(r)
+	}
 }
 
 func (m *MockHealthcheckController) HandlePing(w http.ResponseWriter, r *http.Request) {
@@ -42,44 +78,240 @@ func (m *MockHealthcheckController) HandlePing(w http.ResponseWriter, r *http.Re
 	}
 }
 
+func (m *MockHealthcheckController) HandleLivez(w http.ResponseWriter, r *http.Request) {
+	if m.HandleLivezFunc != nil {
+		m.HandleLivezFunc(w, r)
+	}
+}
+
+func (m *MockHealthcheckController) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	if m.HandleReadyzFunc != nil {
+		m.HandleReadyzFunc(w, r)
+	}
+}
+
+func (m *MockHealthcheckController) HandleStartupz(w http.ResponseWriter, r *http.Request) {
+	if m.HandleStartupzFunc != nil {
+		m.HandleStartupzFunc(w, r)
+	}
+}
+
+// MockDependencyStatus - мок реестра состояний зависимостей для тестирования
+type MockDependencyStatus struct {
+	DependenciesFunc func() []DependencyState
+}
+
+func (m *MockDependencyStatus) Register(name string, required bool) {}
+
+func (m *MockDependencyStatus) Set(name string, err error) {}
+
+func (m *MockDependencyStatus) IsUp(name string) bool {
+	for _, d := range m.Dependencies() {
+		if d.Name == name {
+			return d.Up
+		}
+	}
+	return false
+}
+
+func (m *MockDependencyStatus) Dependencies() []DependencyState {
+	if m.DependenciesFunc != nil {
+		return m.DependenciesFunc()
+	}
+	return nil
+}
+
+// MockAuthorizer - мок проверки прав для тестирования use cases
+type MockAuthorizer struct {
+	AuthorizeFunc func(ctx context.Context, action, resource string) error
+}
+
+// Authorize без AuthorizeFunc разрешает всё
+func (m *MockAuthorizer) Authorize(ctx context.Context, action, resource string) error {
+	if m.AuthorizeFunc != nil {
+		return m.AuthorizeFunc(ctx, action, resource)
+	}
+	return nil
+}
+
+// MockTransactionManager - мок транзакций: fn выполняется сразу с nil транзакцией,
+// поэтому Tx методы моков сервисов не должны обращаться к tx
+type MockTransactionManager struct{}
+
+func (m *MockTransactionManager) Execute(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	return fn(nil)
+}
+
+// MockUserService - мок сервиса пользователей для тестирования use cases
+type MockUserService struct {
+	ListFunc           func(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	GetFunc            func(ctx context.Context, id int64) (*domain.User, error)
+	GetForUpdateTxFunc func(tx *sqlx.Tx, id int64) (*domain.User, error)
+	CreateFunc         func(ctx context.Context, user *domain.User) error
+	UpdateTxFunc       func(tx *sqlx.Tx, user *domain.User) error
+	DeleteFunc         func(ctx context.Context, id int64) error
+}
+
+func (m *MockUserService) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockUserService) Get(ctx context.Context, id int64) (*domain.User, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	return &domain.User{ID: id}, nil
+}
+
+// GetForUpdateTx без GetForUpdateTxFunc делегирует в Get
+func (m *MockUserService) GetForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error) {
+	if m.GetForUpdateTxFunc != nil {
+		return m.GetForUpdateTxFunc(tx, id)
+	}
+	return m.Get(context.Background(), id)
+}
+
+func (m *MockUserService) Create(ctx context.Context, user *domain.User) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, user)
+	}
+	return nil
+}
+
+func (m *MockUserService) CreateTx(tx *sqlx.Tx, user *domain.User) error {
+	return m.Create(context.Background(), user)
+}
+
+func (m *MockUserService) UpdateTx(tx *sqlx.Tx, user *domain.User) error {
+	if m.UpdateTxFunc != nil {
+		return m.UpdateTxFunc(tx, user)
+	}
+	return nil
+}
+
+func (m *MockUserService) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockUserService) DeleteTx(tx *sqlx.Tx, id int64) error {
+	return m.Delete(context.Background(), id)
+}
+
+// MockUserRepository - мок репозитория пользователей для тестирования сервиса
+// и use cases без БД. Методы без ...Tx и Tx методы вызывают одни и те же функции,
+// кроме GetByIDForUpdateTx: так тест видит, что чтение шло с блокировкой строки.
+type MockUserRepository struct {
+	ListFunc               func(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	GetByIDFunc            func(ctx context.Context, id int64) (*domain.User, error)
+	GetByIDForUpdateTxFunc func(tx *sqlx.Tx, id int64) (*domain.User, error)
+	CreateFunc             func(ctx context.Context, user *domain.User) error
+	UpdateFunc             func(ctx context.Context, user *domain.User) error
+	DeleteFunc             func(ctx context.Context, id int64) error
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return &domain.User{ID: id}, nil
+}
+
+// GetByIDForUpdateTx без GetByIDForUpdateTxFunc делегирует в GetByID
+func (m *MockUserRepository) GetByIDForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error) {
+	if m.GetByIDForUpdateTxFunc != nil {
+		return m.GetByIDForUpdateTxFunc(tx, id)
+	}
+	return m.GetByID(context.Background(), id)
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, user)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) CreateTx(tx *sqlx.Tx, user *domain.User) error {
+	return m.Create(context.Background(), user)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, user)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdateTx(tx *sqlx.Tx, user *domain.User) error {
+	return m.Update(context.Background(), user)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) DeleteTx(tx *sqlx.Tx, id int64) error {
+	return m.Delete(context.Background(), id)
+}
+
 // ==================== Примеры использования в тестах ====================
 
 /*
 Пример использования в unit тестах:
 
 func TestHealthcheckService(t *testing.T) {
-	// Мок репозитория с успешным Ping
-	mockRepo := &interfaces.MockHealthcheckRepository{
-		PingFunc: func(ctx context.Context) error {
+	// Проверка, всегда возвращающая успех
+	checks := []interfaces.HealthCheck{{
+		Name:     "postgres",
+		Probes:   []domain.Probe{domain.ProbeReadiness},
+		Required: true,
+		Check: func(ctx context.Context) error {
 			return nil
 		},
-	}
+	}}
 
-	service := NewHealthcheckService(mockRepo)
-	status, err := service.Check(context.Background())
+	service := NewHealthcheckService(checks, services.HealthcheckOptions{CheckTimeout: time.Second}, zap.NewNop())
+	report := service.Check(context.Background(), domain.ProbeReadiness)
 
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	if status["status"] != "ok" {
-		t.Errorf("expected status 'ok', got %v", status["status"])
+	if report.Status != domain.HealthUp {
+		t.Errorf("expected status 'up', got %v", report.Status)
 	}
 }
 
-// Мок репозитория с ошибкой
-func TestHealthcheckServiceWithError(t *testing.T) {
-	mockRepo := &interfaces.MockHealthcheckRepository{
-		PingFunc: func(ctx context.Context) error {
-			return errors.New("connection failed")
+// Зависшая обязательная проверка завершается по таймауту
+func TestHealthcheckServiceTimeout(t *testing.T) {
+	checks := []interfaces.HealthCheck{{
+		Name:     "postgres",
+		Probes:   []domain.Probe{domain.ProbeReadiness},
+		Required: true,
+		Timeout:  10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
 		},
-	}
+	}}
 
-	service := NewHealthcheckService(mockRepo)
-	_, err := service.Check(context.Background())
+	service := NewHealthcheckService(checks, services.HealthcheckOptions{CheckTimeout: time.Second}, zap.NewNop())
+	report := service.Check(context.Background(), domain.ProbeReadiness)
 
-	if err == nil {
-		t.Fatal("expected error, got nil")
+	if report.Status != domain.HealthDown {
+		t.Fatalf("expected status 'down', got %v", report.Status)
 	}
 }
-*/
\ No newline at end of file
+*/
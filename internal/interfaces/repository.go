@@ -1,8 +1,67 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
 
 // HealthcheckRepository интерфейс для репозитория
 type HealthcheckRepository interface {
 	Ping(ctx context.Context) error
+	// MigrationVersion возвращает версию схемы из таблицы версий golang-migrate table
+	MigrationVersion(ctx context.Context, table string) (version uint, dirty bool, err error)
+}
+
+// RBACRepository - роли, разрешения и привязки ролей модуля rbac
+type RBACRepository interface {
+	// ListRoles возвращает роли с разрешениями, упорядоченные по имени
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	GetRole(ctx context.Context, name string) (*domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) error
+	// DeleteRole удаляет роль вместе с её разрешениями и привязками
+	DeleteRole(ctx context.Context, name string) error
+	CreatePermission(ctx context.Context, role string, p *domain.Permission) error
+	DeletePermission(ctx context.Context, role string, id int64) error
+	// ListBindings возвращает страницу привязок и их общее число
+	ListBindings(ctx context.Context, filter domain.RoleBindingFilter) ([]domain.RoleBinding, int64, error)
+	CreateBinding(ctx context.Context, b *domain.RoleBinding) error
+	DeleteBinding(ctx context.Context, id int64) error
+	// SubjectPermissions возвращает разрешения ролей, привязанных к субъекту,
+	// и ролей roles (claim токена при rbac.trust_token_roles, иначе пусто)
+	SubjectPermissions(ctx context.Context, kind, subject string, roles []string) ([]domain.Permission, error)
+}
+
+// APIKeyRepository - API ключи модуля apikeys
+type APIKeyRepository interface {
+	// List возвращает страницу ключей и их общее число, новые - первыми
+	List(ctx context.Context, filter domain.APIKeyFilter) ([]domain.APIKey, int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.APIKey, error)
+	// GetByHash ищет ключ по auth.HashAPIKey текущего или прежнего секрета,
+	// в том числе отозванный и истёкший
+	GetByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	Create(ctx context.Context, key *domain.APIKey) error
+	// Rotate заменяет prefix и hash действующего неистёкшего ключа; прежний hash
+	// принимается ещё gracePeriod (0 - перестаёт действовать сразу)
+	Rotate(ctx context.Context, id int64, prefix, hash string, gracePeriod time.Duration) (*domain.APIKey, error)
+	// Revoke отзывает ключ; повторный отзыв не меняет revoked_at
+	Revoke(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+// UserRepository - пользователи модуля users. Методы с суффиксом Tx
+// выполняются в транзакции вызывающего (docs/TRANSACTIONS.md)
+type UserRepository interface {
+	// List возвращает страницу пользователей и их общее число
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.User, error)
+	// GetByIDForUpdateTx читает пользователя с блокировкой строки до конца транзакции
+	GetByIDForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	CreateTx(tx *sqlx.Tx, user *domain.User) error
+	Update(ctx context.Context, user *domain.User) error
+	UpdateTx(tx *sqlx.Tx, user *domain.User) error
+	Delete(ctx context.Context, id int64) error
+	DeleteTx(tx *sqlx.Tx, id int64) error
 }
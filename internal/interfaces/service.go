@@ -1,8 +1,73 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
 
 // HealthcheckService интерфейс сервиса проверки здоровья
 type HealthcheckService interface {
-	Check(ctx context.Context) (map[string]interface{}, error)
-}
\ No newline at end of file
+	// Check возвращает последние результаты фоновых проверок пробы
+	Check(ctx context.Context, probe domain.Probe) domain.HealthReport
+	// CheckFresh выполняет проверки пробы немедленно
+	CheckFresh(ctx context.Context, probe domain.Probe) domain.HealthReport
+	// Subscribe регистрирует обработчик смены состояния проверок
+	Subscribe(fn func(domain.HealthTransition))
+	// Run обновляет результаты проверок в фоне до отмены ctx
+	Run(ctx context.Context)
+	// Drain переводит readiness пробу в down перед остановкой сервера
+	Drain()
+}
+
+// Authorizer - проверка прав субъекта запроса. Use cases вызывают Authorize
+// перед действием, middleware.Authorize - для маршрута целиком.
+type Authorizer interface {
+	// Authorize возвращает nil, если субъекту из контекста (auth.PrincipalFrom)
+	// разрешено действие action над resource; анонимному - 401, без разрешения - 403
+	Authorize(ctx context.Context, action, resource string) error
+}
+
+// RBACService - проверка прав и управление ролями, разрешениями и привязками
+type RBACService interface {
+	Authorizer
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	GetRole(ctx context.Context, name string) (*domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) error
+	DeleteRole(ctx context.Context, name string) error
+	AddPermission(ctx context.Context, role string, p *domain.Permission) error
+	RemovePermission(ctx context.Context, role string, id int64) error
+	ListBindings(ctx context.Context, filter domain.RoleBindingFilter) ([]domain.RoleBinding, int64, error)
+	Bind(ctx context.Context, b *domain.RoleBinding) error
+	Unbind(ctx context.Context, id int64) error
+}
+
+// APIKeyService - выпуск и отзыв API ключей и их проверка в схеме
+// Authorization: ApiKey (auth.Authenticator для middleware.Authenticate)
+type APIKeyService interface {
+	auth.Authenticator
+	List(ctx context.Context, filter domain.APIKeyFilter) ([]domain.APIKey, int64, error)
+	Get(ctx context.Context, id int64) (*domain.APIKey, error)
+	// Create выпускает ключ; expiresAt nil - бессрочный
+	Create(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*domain.IssuedAPIKey, error)
+	// Rotate выпускает новый секрет ключа с тем же ID, scopes и ролями; старый
+	// действует ещё gracePeriod (0 - перестаёт сразу). Истёкший ключ не ротируется
+	Rotate(ctx context.Context, id int64, gracePeriod time.Duration) (*domain.IssuedAPIKey, error)
+	Revoke(ctx context.Context, id int64) error
+}
+
+// UserService - пользователи: email приводится к нижнему регистру перед записью.
+// Методы с суффиксом Tx выполняются в транзакции use case.
+type UserService interface {
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	Get(ctx context.Context, id int64) (*domain.User, error)
+	// GetForUpdateTx читает пользователя с блокировкой строки до конца транзакции
+	GetForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	CreateTx(tx *sqlx.Tx, user *domain.User) error
+	UpdateTx(tx *sqlx.Tx, user *domain.User) error
+	Delete(ctx context.Context, id int64) error
+	DeleteTx(tx *sqlx.Tx, id int64) error
+}
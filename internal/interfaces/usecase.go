@@ -1,7 +1,26 @@
 package interfaces
 
-// TODO: добавляйте интерфейсы use cases по мере необходимости
-// Пример:
-// type CreateUserUseCase interface {
-//     Execute(ctx context.Context, req CreateUserRequest) (*User, error)
-// }
\ No newline at end of file
+import (
+	"context"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// TransactionManager выполняет fn в транзакции: ошибка или паника fn - ROLLBACK,
+// иначе COMMIT (реализация - db.TransactionManager из toolbox, docs/TRANSACTIONS.md)
+type TransactionManager interface {
+	Execute(ctx context.Context, fn func(tx *sqlx.Tx) error) error
+}
+
+// UserUsecase - сценарии модуля users. Права субъекта проверяются через
+// Authorizer: действия read, create, update, delete над users (список, создание)
+// и users:<id> (конкретный пользователь)
+type UserUsecase interface {
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	Get(ctx context.Context, id int64) (*domain.User, error)
+	Create(ctx context.Context, name, email string) (*domain.User, error)
+	// Update меняет заданные поля patch; параллельные изменения не теряются
+	Update(ctx context.Context, id int64, patch domain.UserPatch) (*domain.User, error)
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,213 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// defaultRollbackTimeout - дедлайн остановки уже запущенных хуков при ошибке Start
+const defaultRollbackTimeout = 30 * time.Second
+
+// HookError - ошибка хука конкретного компонента
+type HookError struct {
+	Name  string
+	Phase string
+	Err   error
+	// Hung - хук не завершился до дедлайна контекста вызывающего
+	Hung bool
+}
+
+func (e *HookError) Error() string {
+	if e.Hung {
+		return fmt.Sprintf("%s: %s hung: %v", e.Name, e.Phase, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Name, e.Phase, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// Manager запускает компоненты в порядке регистрации (порядке зависимостей)
+// и останавливает в обратном. Реализует interfaces.Lifecycle.
+type Manager struct {
+	mu    sync.Mutex
+	hooks []interfaces.Hook
+	// hookStopped[i] - хук hooks[i] уже остановлен (StopStage или откат Start)
+	hookStopped []bool
+	started     bool
+	stopped     bool
+	// lateErrs - ошибки запуска хуков, добавленных после Start
+	lateErrs []error
+	// rollbackTimeout - дедлайн отката Start, не зависящий от контекста Start
+	rollbackTimeout time.Duration
+}
+
+func NewManager() *Manager {
+	return &Manager{rollbackTimeout: defaultRollbackTimeout}
+}
+
+// Append регистрирует хуки компонента. Если Start уже был вызван,
+// OnStart выполняется сразу, а его ошибка будет возвращена из Stop.
+func (m *Manager) Append(hook interfaces.Hook) {
+	m.mu.Lock()
+	started := m.started
+	m.mu.Unlock()
+
+	if started && hook.OnStart != nil {
+		if err := hook.OnStart(context.Background()); err != nil {
+			m.mu.Lock()
+			m.lateErrs = append(m.lateErrs, &HookError{Name: hook.Name, Phase: "start", Err: err})
+			m.mu.Unlock()
+			return
+		}
+	}
+
+	m.mu.Lock()
+	m.hooks = append(m.hooks, hook)
+	m.hookStopped = append(m.hookStopped, false)
+	m.mu.Unlock()
+}
+
+// Start выполняет OnStart в порядке регистрации. При ошибке уже запущенные
+// компоненты останавливаются в обратном порядке с дедлайном, не зависящим
+// от ctx, возвращаются все ошибки.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return errors.New("lifecycle: already started")
+	}
+	m.started = true
+	hooks := append([]interfaces.Hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	for i, hook := range hooks {
+		if hook.OnStart == nil {
+			continue
+		}
+		if err := runHook(ctx, hook.OnStart); err != nil {
+			startErr := &HookError{Name: hook.Name, Phase: "start", Err: err, Hung: isDeadline(ctx, err)}
+			return errors.Join(startErr, m.rollback(i))
+		}
+	}
+
+	return nil
+}
+
+// rollback останавливает хуки, запущенные до хука failed, в том же порядке,
+// что и Stop: по этапам, внутри этапа - в обратном порядке регистрации.
+// Сам failed не запустился, и его OnStop не вызывается. Контекст Start может
+// быть уже исчерпан зависшим OnStart, поэтому у отката свой дедлайн rollbackTimeout.
+func (m *Manager) rollback(failed int) error {
+	m.mu.Lock()
+	m.hookStopped[failed] = true
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.rollbackTimeout)
+	defer cancel()
+	return errors.Join(
+		m.stopStage(ctx, interfaces.StageComponent, failed),
+		m.stopStage(ctx, interfaces.StageConnection, failed),
+	)
+}
+
+// Stop выполняет OnStop ещё не остановленных хуков: сначала этапа
+// interfaces.StageComponent, затем interfaces.StageConnection, внутри этапа -
+// в обратном порядке регистрации. Каждый хук получает контекст вызывающего;
+// хук, не уложившийся в дедлайн, помечается как зависший, и остановка
+// продолжается со следующего. Повторный вызов ничего не делает.
+// Возвращает все ошибки, объединённые через errors.Join.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	errs := append([]error(nil), m.lateErrs...)
+	m.mu.Unlock()
+
+	errs = append(errs,
+		m.StopStage(ctx, interfaces.StageComponent),
+		m.StopStage(ctx, interfaces.StageConnection),
+	)
+	return errors.Join(errs...)
+}
+
+// StopStage останавливает ещё не остановленные хуки этапа stage в обратном
+// порядке регистрации. Позволяет задать каждому этапу свой дедлайн;
+// оставшиеся хуки остановит Stop.
+func (m *Manager) StopStage(ctx context.Context, stage interfaces.HookStage) error {
+	return m.stopStage(ctx, stage, -1)
+}
+
+// stopStage - StopStage для первых n хуков, n < 0 - для всех
+func (m *Manager) stopStage(ctx context.Context, stage interfaces.HookStage, n int) error {
+	m.mu.Lock()
+	var hooks []interfaces.Hook
+	for i, hook := range m.hooks {
+		if i == n {
+			break
+		}
+		if hook.Stage == stage && !m.hookStopped[i] {
+			hooks = append(hooks, hook)
+			m.hookStopped[i] = true
+		}
+	}
+	m.mu.Unlock()
+
+	return stopHooks(ctx, hooks)
+}
+
+func stopHooks(ctx context.Context, hooks []interfaces.Hook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			// Дедлайн исчерпан предыдущими компонентами
+			errs = append(errs, &HookError{Name: hook.Name, Phase: "stop", Err: fmt.Errorf("skipped: %w", err)})
+			continue
+		}
+		if err := runHook(ctx, hook.OnStop); err != nil {
+			hookErr := &HookError{Name: hook.Name, Phase: "stop", Err: err, Hung: isDeadline(ctx, err)}
+			if hookErr.Hung {
+				log.Printf("Component %s did not stop before deadline", hook.Name)
+			}
+			errs = append(errs, hookErr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runHook выполняет хук и ждёт его завершения, но не дольше дедлайна ctx
+func runHook(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isDeadline(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && errors.Is(err, ctx.Err())
+}
@@ -0,0 +1,225 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// recorder записывает вызовы хуков по порядку
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, ",")
+}
+
+// hook возвращает хук name, который записывает вызовы и возвращает startErr и stopErr
+func (r *recorder) hook(name string, startErr, stopErr error) interfaces.Hook {
+	return interfaces.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			r.record("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+// hang - хук, который игнорирует ctx и не завершается до конца теста
+func hang(t *testing.T) func(ctx context.Context) error {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	return func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+}
+
+func hookError(t *testing.T, err error, name string) *HookError {
+	t.Helper()
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			if hookErr := hookError(t, e, name); hookErr != nil {
+				return hookErr
+			}
+		}
+		return nil
+	}
+	var hookErr *HookError
+	if errors.As(err, &hookErr) && hookErr.Name == name {
+		return hookErr
+	}
+	return nil
+}
+
+func TestStartStopOrder(t *testing.T) {
+	r := &recorder{}
+	m := NewManager()
+	for _, name := range []string{"db", "broker", "consumer"} {
+		m.Append(r.hook(name, nil, nil))
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := "start db,start broker,start consumer,stop consumer,stop broker,stop db"
+	if got := r.String(); got != want {
+		t.Fatalf("calls = %s, want %s", got, want)
+	}
+
+	// Повторный Stop ничего не делает
+	if err := m.Stop(context.Background()); err != nil || r.String() != want {
+		t.Fatalf("second Stop: %v, calls %s", err, r)
+	}
+	if err := m.Start(context.Background()); err == nil {
+		t.Fatal("second Start accepted")
+	}
+}
+
+func TestStopDeadline(t *testing.T) {
+	r := &recorder{}
+	m := NewManager()
+	m.Append(r.hook("db", nil, nil))
+	m.Append(interfaces.Hook{Name: "consumer", OnStop: hang(t)})
+	m.Append(r.hook("producer", nil, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := m.Stop(ctx)
+
+	if hookErr := hookError(t, err, "consumer"); hookErr == nil || !hookErr.Hung || !errors.Is(hookErr, context.DeadlineExceeded) {
+		t.Fatalf("consumer: %v, want hung", err)
+	}
+	// Хуки после зависшего пропускаются: дедлайн уже исчерпан
+	if hookErr := hookError(t, err, "db"); hookErr == nil || hookErr.Hung || !strings.Contains(hookErr.Error(), "skipped") {
+		t.Fatalf("db: %v, want skipped", err)
+	}
+	if got := r.String(); got != "stop producer" {
+		t.Fatalf("calls = %s, want only producer stopped", got)
+	}
+}
+
+func TestStartRollback(t *testing.T) {
+	r := &recorder{}
+	failed := errors.New("connection refused")
+	m := NewManager()
+	m.Append(r.hook("db", nil, nil))
+	m.Append(r.hook("broker", nil, nil))
+	m.Append(r.hook("consumer", failed, nil))
+	m.Append(r.hook("producer", nil, nil))
+
+	err := m.Start(context.Background())
+	if !errors.Is(err, failed) {
+		t.Fatalf("Start = %v, want consumer error", err)
+	}
+	if hookErr := hookError(t, err, "consumer"); hookErr == nil || hookErr.Phase != "start" || hookErr.Hung {
+		t.Fatalf("Start = %v, want consumer start error", err)
+	}
+	want := "start db,start broker,start consumer,stop broker,stop db"
+	if got := r.String(); got != want {
+		t.Fatalf("calls = %s, want %s", got, want)
+	}
+
+	// Откаченные хуки и хук с ошибкой запуска не останавливаются повторно
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.String(); got != want+",stop producer" {
+		t.Fatalf("calls after Stop = %s", got)
+	}
+}
+
+func TestStartRollbackAfterHang(t *testing.T) {
+	r := &recorder{}
+	m := NewManager()
+	m.Append(r.hook("db", nil, nil))
+	m.Append(interfaces.Hook{Name: "consumer", OnStart: hang(t)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := m.Start(ctx)
+
+	if hookErr := hookError(t, err, "consumer"); hookErr == nil || !hookErr.Hung {
+		t.Fatalf("Start = %v, want consumer hung", err)
+	}
+	// Контекст Start исчерпан, но откат выполняется со своим дедлайном
+	if hookErr := hookError(t, err, "db"); hookErr != nil {
+		t.Fatalf("db rollback: %v", hookErr)
+	}
+	if got := r.String(); got != "start db,stop db" {
+		t.Fatalf("calls = %s, want db stopped", got)
+	}
+}
+
+func TestStopErrors(t *testing.T) {
+	r := &recorder{}
+	dbErr := errors.New("db close failed")
+	brokerErr := errors.New("broker close failed")
+	lateErr := errors.New("late start failed")
+
+	m := NewManager()
+	m.Append(r.hook("db", nil, dbErr))
+	m.Append(r.hook("broker", nil, brokerErr))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Хук, добавленный после Start, запускается сразу; ошибка вернётся из Stop
+	m.Append(r.hook("late", lateErr, nil))
+
+	err := m.Stop(context.Background())
+	for _, want := range []error{dbErr, brokerErr, lateErr} {
+		if !errors.Is(err, want) {
+			t.Fatalf("Stop = %v, missing %v", err, want)
+		}
+	}
+	if hookErr := hookError(t, err, "broker"); hookErr == nil || hookErr.Phase != "stop" {
+		t.Fatalf("Stop = %v, want broker stop error", err)
+	}
+	if got := r.String(); got != "start db,start broker,start late,stop broker,stop db" {
+		t.Fatalf("calls = %s", got)
+	}
+}
+
+func TestStartRollbackStages(t *testing.T) {
+	r := &recorder{}
+	m := NewManager()
+	db := r.hook("db", nil, nil)
+	db.Stage = interfaces.StageConnection
+	m.Append(db)
+	m.Append(r.hook("consumer", nil, nil))
+	logger := r.hook("logger", nil, nil)
+	logger.Stage = interfaces.StageConnection
+	m.Append(logger)
+	m.Append(r.hook("producer", errors.New("broker unavailable"), nil))
+
+	if err := m.Start(context.Background()); err == nil {
+		t.Fatal("Start succeeded")
+	}
+	// Откат идёт тем же порядком, что и Stop: компоненты раньше подключений
+	want := "start db,start consumer,start logger,start producer,stop consumer,stop logger,stop db"
+	if got := r.String(); got != want {
+		t.Fatalf("calls = %s, want %s", got, want)
+	}
+}
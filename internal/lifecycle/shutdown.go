@@ -0,0 +1,62 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+)
+
+// ServerShutdown - этапы graceful shutdown HTTP сервера. Run выполняет их по порядку:
+//  1. Drain переводит readiness в down, затем DrainPeriod ожидается, чтобы
+//     балансировщик перестал направлять запросы (повторный сигнал прерывает ожидание);
+//  2. StopServer перестаёт принимать соединения и завершает активные запросы
+//     с дедлайном HTTPTimeout;
+//  3. StopContainer останавливает компоненты и закрывает подключения.
+//
+// Ошибка StopServer не прерывает остановку: Run возвращает все ошибки.
+type ServerShutdown struct {
+	Drain         func()
+	DrainPeriod   time.Duration
+	StopServer    func(ctx context.Context) error
+	HTTPTimeout   time.Duration
+	StopContainer func() error
+}
+
+// Run выполняет остановку; signals - канал сигналов ОС, первый сигнал уже получен
+func (s ServerShutdown) Run(signals <-chan os.Signal) error {
+	if s.Drain != nil {
+		s.Drain()
+	}
+
+	if s.DrainPeriod > 0 {
+		log.Printf("Draining for %s...", s.DrainPeriod)
+		timer := time.NewTimer(s.DrainPeriod)
+		select {
+		case <-timer.C:
+		case sig := <-signals:
+			timer.Stop()
+			log.Printf("Received signal: %v, skipping drain", sig)
+		}
+	}
+
+	var errs []error
+
+	log.Println("Shutting down server gracefully...")
+	ctx, cancel := context.WithTimeout(context.Background(), s.HTTPTimeout)
+	defer cancel()
+	if err := s.StopServer(ctx); err != nil {
+		log.Printf("Error during server shutdown: %v", err)
+		errs = append(errs, err)
+	} else {
+		log.Println("Server shut down successfully")
+	}
+
+	if err := s.StopContainer(); err != nil {
+		log.Printf("Error during container shutdown: %v", err)
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
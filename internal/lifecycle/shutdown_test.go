@@ -0,0 +1,89 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// recordedShutdown - ServerShutdown, этапы которого записываются в r
+func recordedShutdown(r *recorder, drainPeriod time.Duration) ServerShutdown {
+	return ServerShutdown{
+		Drain:       func() { r.record("drain") },
+		DrainPeriod: drainPeriod,
+		StopServer: func(ctx context.Context) error {
+			if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+				return errors.New("server stopped without http_timeout deadline")
+			}
+			r.record("server")
+			return nil
+		},
+		HTTPTimeout: time.Second,
+		StopContainer: func() error {
+			r.record("container")
+			return nil
+		},
+	}
+}
+
+func TestServerShutdownOrder(t *testing.T) {
+	r := &recorder{}
+	s := recordedShutdown(r, 50*time.Millisecond)
+
+	started := time.Now()
+	if err := s.Run(make(chan os.Signal)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed < 50*time.Millisecond {
+		t.Fatalf("shutdown took %s, want drain period to be waited", elapsed)
+	}
+	if got := r.String(); got != "drain,server,container" {
+		t.Fatalf("phases = %s, want drain,server,container", got)
+	}
+}
+
+func TestServerShutdownSecondSignalSkipsDrain(t *testing.T) {
+	r := &recorder{}
+	s := recordedShutdown(r, time.Hour)
+
+	signals := make(chan os.Signal, 1)
+	signals <- syscall.SIGTERM
+	done := make(chan error, 1)
+	go func() { done <- s.Run(signals) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not skip the drain period")
+	}
+	if got := r.String(); got != "drain,server,container" {
+		t.Fatalf("phases = %s, want drain,server,container", got)
+	}
+}
+
+func TestServerShutdownErrors(t *testing.T) {
+	r := &recorder{}
+	serverErr := errors.New("server shutdown timed out")
+	containerErr := errors.New("db close failed")
+	s := recordedShutdown(r, 0)
+	s.StopServer = func(ctx context.Context) error { return serverErr }
+	s.StopContainer = func() error {
+		r.record("container")
+		return containerErr
+	}
+
+	// Ошибка сервера не мешает остановить контейнер
+	err := s.Run(nil)
+	if !errors.Is(err, serverErr) || !errors.Is(err, containerErr) {
+		t.Fatalf("Run = %v, want both errors", err)
+	}
+	if got := r.String(); got != "drain,container" {
+		t.Fatalf("phases = %s", got)
+	}
+}
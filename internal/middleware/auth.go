@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// Authenticate проверяет заголовок Authorization аутентификатором его схемы
+// и кладёт субъекта в контекст запроса (auth.PrincipalFrom). Запрос без
+// заголовка проходит анонимным - доступ ограничивают RequireAuth и RequireScope.
+// Неверные учётные данные и неизвестная схема - 401 с WWW-Authenticate,
+// внутренняя ошибка аутентификатора (недоступна БД ключей) - 500 и запись в журнал.
+func Authenticate(logger *zap.Logger, authenticators ...auth.Authenticator) Middleware {
+	byScheme := make(map[string]auth.Authenticator, len(authenticators))
+	schemes := make([]string, 0, len(authenticators))
+	for _, a := range authenticators {
+		byScheme[strings.ToLower(a.Scheme())] = a
+		schemes = append(schemes, a.Scheme())
+	}
+	challenge := strings.Join(schemes, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scheme, credentials, ok := auth.ParseAuthorization(r)
+			a, known := byScheme[strings.ToLower(scheme)]
+			if !ok || !known {
+				unauthorized(w, r, challenge, apperrors.Unauthorized("unsupported authorization scheme").WithCode("invalid_request"))
+				return
+			}
+
+			p, err := a.Authenticate(r.Context(), credentials)
+			if err != nil {
+				switch e := apperrors.As(err); e.Kind {
+				case apperrors.KindUnauthorized:
+					unauthorized(w, r, a.Scheme()+` error="invalid_token"`, e)
+					return
+				case apperrors.KindInternal:
+					logger.Error("Authentication failed", zap.String("scheme", a.Scheme()), zap.Error(err))
+				}
+				apperrors.Write(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// RequireAuth пропускает только аутентифицированные запросы, остальным - 401.
+// Работает после Authenticate (стек middleware сервера).
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := auth.RequirePrincipal(r.Context()); err != nil {
+			unauthorized(w, r, "Bearer", err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope пропускает запросы субъекта со всеми scopes: анонимному - 401,
+// при нехватке scope - 403 с кодом insufficient_scope
+func RequireScope(scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := auth.RequireScope(r.Context(), scopes...); err != nil {
+				if apperrors.As(err).Kind == apperrors.KindUnauthorized {
+					unauthorized(w, r, "Bearer", err)
+					return
+				}
+				apperrors.Write(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Authorize пропускает запросы субъекта, которому authz разрешает action над
+// resource: анонимному - 401, без разрешения - 403 с кодом permission_denied.
+// Для проверок, зависящих от тела запроса или загруженной сущности,
+// use case вызывает Authorizer.Authorize сам. Внутренние ошибки (недоступна БД) - 500
+// и запись в журнал.
+func Authorize(authz interfaces.Authorizer, action, resource string, logger *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := authz.Authorize(r.Context(), action, resource); err != nil {
+				switch apperrors.As(err).Kind {
+				case apperrors.KindUnauthorized:
+					unauthorized(w, r, "Bearer", err)
+					return
+				case apperrors.KindInternal:
+					logger.Error("Authorization failed",
+						zap.String("action", action),
+						zap.String("resource", resource),
+						zap.Error(err))
+				}
+				apperrors.Write(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// unauthorized отвечает 401 с заголовком WWW-Authenticate (RFC 9110)
+func unauthorized(w http.ResponseWriter, r *http.Request, challenge string, err error) {
+	if challenge != "" {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	apperrors.Write(w, r, err)
+}
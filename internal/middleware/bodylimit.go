@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+// MaxBodySize ограничивает размер тела запроса: запрос с Content-Length больше
+// limit сразу получает 413, при чтении сверх лимита обработчик получает
+// *http.MaxBytesError. Внутри группы маршрутов лимит можно только сократить.
+func MaxBodySize(limit int64) Middleware {
+	return RouteBodyLimits(limit, nil)
+}
+
+// RouteBodyLimits - MaxBodySize с лимитами по префиксу пути (самый длинный префикс)
+func RouteBodyLimits(fallback int64, routes map[string]int64) Middleware {
+	matcher := newPrefixMatcher(fallback, routes)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := matcher.match(r.URL.Path)
+			if r.ContentLength > limit {
+				apperrors.Write(w, r, apperrors.New(apperrors.KindPayloadTooLarge, "request body too large"))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
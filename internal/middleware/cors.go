@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// CORS отвечает на preflight запросы (OPTIONS с Access-Control-Request-Method)
+// и добавляет заголовки Access-Control-* к ответам для разрешённых источников.
+// Запрос с неразрешённого источника не отклоняется - браузер сам не отдаст
+// ответ скрипту. Своя политика группы маршрутов подключается через r.Route,
+// а не r.Group: иначе до middleware не дойдут OPTIONS запросы.
+func CORS(policy interfaces.CORSPolicy) Middleware {
+	c := newCORS(policy)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				c.preflight(w, r, origin)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if origin != "" && c.originAllowed(origin) {
+				c.setOrigin(h, origin)
+				if len(c.exposedHeaders) > 0 {
+					h.Set("Access-Control-Expose-Headers", c.exposedHeaders)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type cors struct {
+	anyOrigin        bool
+	origins          map[string]bool
+	wildcards        [][2]string
+	methods          map[string]bool
+	allowedMethods   string
+	anyHeader        bool
+	headers          map[string]bool
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+func newCORS(policy interfaces.CORSPolicy) *cors {
+	c := &cors{
+		origins:          make(map[string]bool),
+		methods:          make(map[string]bool),
+		headers:          make(map[string]bool),
+		allowCredentials: policy.AllowCredentials,
+	}
+	for _, origin := range policy.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			c.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			c.wildcards = append(c.wildcards, [2]string{prefix, suffix})
+		default:
+			c.origins[origin] = true
+		}
+	}
+
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodHead}
+	if len(policy.AllowedMethods) > 0 {
+		methods = make([]string, len(policy.AllowedMethods))
+		for i, method := range policy.AllowedMethods {
+			methods[i] = strings.ToUpper(method)
+		}
+	}
+	for _, method := range methods {
+		c.methods[method] = true
+	}
+	c.allowedMethods = strings.Join(methods, ", ")
+
+	for _, header := range policy.AllowedHeaders {
+		if header == "*" {
+			c.anyHeader = true
+			continue
+		}
+		c.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	c.exposedHeaders = strings.Join(policy.ExposedHeaders, ", ")
+	if policy.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(policy.MaxAge.Seconds()))
+	}
+	return c
+}
+
+// preflight отвечает 204; без заголовков Access-Control-Allow-* браузер
+// не отправит сам запрос
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	requested := requestedHeaders(r.Header.Get("Access-Control-Request-Headers"))
+	if origin == "" || !c.originAllowed(origin) || !c.methodAllowed(method) || !c.headersAllowed(requested) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	c.setOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", c.allowedMethods)
+	if len(requested) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if c.maxAge != "" {
+		h.Set("Access-Control-Max-Age", c.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) setOrigin(h http.Header, origin string) {
+	if c.anyOrigin && !c.allowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.allowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *cors) originAllowed(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if c.origins[origin] {
+		return true
+	}
+	for _, w := range c.wildcards {
+		if len(origin) > len(w[0])+len(w[1]) && strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodAllowed: GET, HEAD и POST по спецификации Fetch разрешены всегда
+func (c *cors) methodAllowed(method string) bool {
+	return c.methods[method] || method == http.MethodGet || method == http.MethodHead || method == http.MethodPost
+}
+
+func (c *cors) headersAllowed(headers []string) bool {
+	if c.anyHeader {
+		return true
+	}
+	for _, header := range headers {
+		if !c.headers[header] {
+			return false
+		}
+	}
+	return true
+}
+
+func requestedHeaders(raw string) []string {
+	var headers []string
+	for _, header := range strings.Split(raw, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+	return headers
+}
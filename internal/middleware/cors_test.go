@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// corsRequest выполняет запрос через CORS(policy) и сообщает, дошёл ли он до обработчика
+func corsRequest(policy interfaces.CORSPolicy, r *http.Request) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	h := CORS(policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec, reached
+}
+
+func preflightRequest(origin, method, headers string) *http.Request {
+	r := httptest.NewRequest(http.MethodOptions, "/api/v1/users", nil)
+	r.Header.Set("Origin", origin)
+	r.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		r.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return r
+}
+
+func TestCORSPreflight(t *testing.T) {
+	policy := interfaces.CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods: []string{"get", "patch", "delete"},
+		AllowedHeaders: []string{"Authorization", "content-type"},
+		MaxAge:         10 * time.Minute,
+	}
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+		allowed bool
+	}{
+		{"allowed origin", "https://app.example.com", "PATCH", "authorization, Content-Type", true},
+		{"origin case", "https://APP.example.com", "DELETE", "", true},
+		{"wildcard origin", "https://admin.example.org", "PATCH", "", true},
+		{"simple method always allowed", "https://app.example.com", "POST", "", true},
+		{"wildcard needs subdomain", "https://.example.org", "PATCH", "", false},
+		{"disallowed origin", "https://evil.example.net", "PATCH", "", false},
+		{"disallowed method", "https://app.example.com", "PUT", "", false},
+		{"disallowed header", "https://app.example.com", "PATCH", "X-Debug", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, reached := corsRequest(policy, preflightRequest(tt.origin, tt.method, tt.headers))
+			if reached {
+				t.Fatal("preflight reached the handler")
+			}
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want 204", rec.Code)
+			}
+			if vary := strings.Join(rec.Header().Values("Vary"), ", "); vary != "Origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+				t.Fatalf("Vary = %q", vary)
+			}
+			h := rec.Header()
+			if !tt.allowed {
+				if origin := h.Get("Access-Control-Allow-Origin"); origin != "" || h.Get("Access-Control-Allow-Methods") != "" {
+					t.Fatalf("disallowed preflight got CORS headers: %v", h)
+				}
+				return
+			}
+			if h.Get("Access-Control-Allow-Origin") != tt.origin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", h.Get("Access-Control-Allow-Origin"), tt.origin)
+			}
+			if h.Get("Access-Control-Allow-Methods") != "GET, PATCH, DELETE" {
+				t.Fatalf("Access-Control-Allow-Methods = %q", h.Get("Access-Control-Allow-Methods"))
+			}
+			if h.Get("Access-Control-Max-Age") != "600" {
+				t.Fatalf("Access-Control-Max-Age = %q, want 600", h.Get("Access-Control-Max-Age"))
+			}
+			if h.Get("Access-Control-Allow-Credentials") != "" {
+				t.Fatal("credentials allowed without AllowCredentials")
+			}
+		})
+	}
+
+	rec, _ := corsRequest(policy, preflightRequest("https://app.example.com", "PATCH", "authorization,content-type"))
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Authorization, Content-Type" {
+		t.Fatalf("Access-Control-Allow-Headers = %q", got)
+	}
+
+	// Без MaxAge браузер использует своё значение по умолчанию
+	rec, _ = corsRequest(interfaces.CORSPolicy{AllowedOrigins: []string{"*"}}, preflightRequest("https://a.test", "GET", ""))
+	if _, ok := rec.Header()["Access-Control-Max-Age"]; ok {
+		t.Fatalf("Access-Control-Max-Age = %q without MaxAge", rec.Header().Get("Access-Control-Max-Age"))
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	withCredentials := interfaces.CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+
+	// Браузер отклоняет "*" вместе с credentials, поэтому источник отражается
+	rec, _ := corsRequest(withCredentials, preflightRequest("https://app.example.com", "GET", ""))
+	for _, h := range []http.Header{rec.Header(), simpleCORS(t, withCredentials, "https://app.example.com").Header()} {
+		if h.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+			t.Fatalf("Access-Control-Allow-Origin = %q, want the request origin", h.Get("Access-Control-Allow-Origin"))
+		}
+		if h.Get("Access-Control-Allow-Credentials") != "true" {
+			t.Fatal("missing Access-Control-Allow-Credentials")
+		}
+	}
+
+	rec = simpleCORS(t, interfaces.CORSPolicy{AllowedOrigins: []string{"*"}}, "https://app.example.com")
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Fatalf("headers without credentials = %v", rec.Header())
+	}
+}
+
+// simpleCORS выполняет GET с Origin и проверяет, что он дошёл до обработчика
+func simpleCORS(t *testing.T, policy interfaces.CORSPolicy, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	rec, reached := corsRequest(policy, r)
+	if !reached || rec.Code != http.StatusOK {
+		t.Fatalf("request did not reach the handler: %d", rec.Code)
+	}
+	return rec
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	policy := interfaces.CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com"},
+		ExposedHeaders: []string{"X-Total-Count", "Link"},
+	}
+
+	rec := simpleCORS(t, policy, "https://app.example.com")
+	h := rec.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://app.example.com" || h.Get("Access-Control-Expose-Headers") != "X-Total-Count, Link" {
+		t.Fatalf("headers = %v", h)
+	}
+
+	// Vary: Origin нужен всем ответам, иначе кеш отдаст ответ одному источнику другому
+	for _, origin := range []string{"https://app.example.com", "https://evil.example.net", ""} {
+		rec := simpleCORS(t, policy, origin)
+		if rec.Header().Get("Vary") != "Origin" {
+			t.Fatalf("origin %q: Vary = %q, want Origin", origin, rec.Header().Get("Vary"))
+		}
+		if origin != "https://app.example.com" && rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("origin %q got Access-Control-Allow-Origin", origin)
+		}
+	}
+
+	// OPTIONS без Access-Control-Request-Method - обычный запрос, а не preflight
+	r := httptest.NewRequest(http.MethodOptions, "/api/v1/users", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	if _, reached := corsRequest(policy, r); !reached {
+		t.Fatal("plain OPTIONS did not reach the handler")
+	}
+}
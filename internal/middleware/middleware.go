@@ -0,0 +1,89 @@
+// Package middleware - HTTP middleware сервера: request ID, восстановление после
+// паники, журнал запросов, реальный IP клиента за доверенными прокси, CORS,
+// аутентификация, rate limiting, дедлайны и лимит размера тела запроса. Stack собирает их в порядке применения по конфигурации.
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// Middleware - обёртка http.Handler, совместимая с chi.Router.Use
+type Middleware = func(http.Handler) http.Handler
+
+// Stack возвращает включённые в конфигурации middleware в порядке применения:
+// реальный IP и request ID нужны журналу запросов, журнал видит ответ 500
+// после восстановления от паники, CORS отвечает на preflight до лимитов,
+// лимиты действуют только на обработчик. Аутентификация выполняется до rate limiting,
+// чтобы лимит считался по субъекту; перед ней запросы с Authorization ограничивает
+// лимит rate_limit.auth по IP. rateLimiter нужен при включённом rate_limit,
+// без authenticators запросы не аутентифицируются.
+func Stack(cf interfaces.ConfigMiddleware, logger *zap.Logger, rateLimiter *RateLimiter, authenticators []auth.Authenticator) []Middleware {
+	var stack []Middleware
+	if cf.GetRealIPEnabled() {
+		stack = append(stack, RealIP(cf.GetRealIPTrustedProxies()))
+	}
+	if cf.GetRequestIDEnabled() {
+		stack = append(stack, RequestID(cf.GetRequestIDHeader()))
+	}
+	if cf.GetAccessLogEnabled() {
+		stack = append(stack, AccessLog(logger, cf.GetAccessLogSkipPaths()))
+	}
+	if cf.GetRecoveryEnabled() {
+		stack = append(stack, Recovery(logger))
+	}
+	if cf.GetCORSEnabled() {
+		stack = append(stack, CORS(cf.GetCORSPolicy()))
+	}
+	if len(authenticators) > 0 {
+		if cf.GetRateLimitEnabled() && rateLimiter != nil {
+			stack = append(stack, rateLimiter.AuthMiddleware())
+		}
+		stack = append(stack, Authenticate(logger, authenticators...))
+	}
+	if cf.GetRateLimitEnabled() && rateLimiter != nil {
+		stack = append(stack, rateLimiter.Middleware())
+	}
+	if cf.GetBodyLimitEnabled() {
+		stack = append(stack, RouteBodyLimits(cf.GetBodyLimitMaxBytes(), cf.GetBodyLimitRoutes()))
+	}
+	if cf.GetTimeoutEnabled() {
+		stack = append(stack, RouteTimeouts(cf.GetTimeoutDefault(), cf.GetTimeoutRoutes()))
+	}
+	return stack
+}
+
+// prefixMatcher выбирает значение самого длинного префикса пути. Префикс
+// совпадает по границе сегментов: "/api/v1/users" относится к "/api/v1/users/42",
+// но не к "/api/v1/users-export"
+type prefixMatcher[T any] struct {
+	prefixes []string
+	values   map[string]T
+	fallback T
+}
+
+func newPrefixMatcher[T any](fallback T, routes map[string]T) *prefixMatcher[T] {
+	m := &prefixMatcher[T]{values: routes, fallback: fallback}
+	for prefix := range routes {
+		m.prefixes = append(m.prefixes, prefix)
+	}
+	sort.Slice(m.prefixes, func(i, j int) bool {
+		return len(m.prefixes[i]) > len(m.prefixes[j])
+	})
+	return m
+}
+
+func (m *prefixMatcher[T]) match(path string) T {
+	for _, prefix := range m.prefixes {
+		base := strings.TrimSuffix(prefix, "/")
+		if path == base || strings.HasPrefix(path, base+"/") {
+			return m.values[prefix]
+		}
+	}
+	return m.fallback
+}
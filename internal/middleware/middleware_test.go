@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPrefixMatcherSegmentBoundary(t *testing.T) {
+	m := newPrefixMatcher("default", map[string]string{
+		"/api/v1/users":   "users",
+		"/api/v1/admin/":  "admin",
+		"/api/v1/reports": "reports",
+	})
+	tests := map[string]string{
+		"/api/v1/users":         "users",
+		"/api/v1/users/42":      "users",
+		"/api/v1/users-export":  "default",
+		"/api/v1/usersettings":  "default",
+		"/api/v1/admin":         "admin",
+		"/api/v1/admin/roles":   "admin",
+		"/api/v1/reports/daily": "reports",
+	}
+	for path, want := range tests {
+		if got := m.match(path); got != want {
+			t.Errorf("match(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestMaxTimeout(t *testing.T) {
+	routes := map[string]time.Duration{"/api/v1/reports": 2 * time.Minute, "/api/v1/search": time.Second}
+	if got := MaxTimeout(10*time.Second, routes); got != 2*time.Minute {
+		t.Fatalf("MaxTimeout = %s, want 2m", got)
+	}
+	if got := MaxTimeout(10*time.Second, nil); got != 10*time.Second {
+		t.Fatalf("MaxTimeout without routes = %s, want 10s", got)
+	}
+}
+
+func TestNewRequestIDFallback(t *testing.T) {
+	readRandom = func([]byte) (int, error) { return 0, errors.New("entropy source unavailable") }
+	defer func() { readRandom = rand.Read }()
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := newRequestID()
+		if len(id) != 32 || !validRequestID(id) || seen[id] {
+			t.Fatalf("fallback id %q is invalid or repeated", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/ratelimit"
+	"github.com/SmirnovND/gobase/internal/reqctx"
+	"go.uber.org/zap"
+)
+
+// rateRule - лимит и область его счётчиков
+type rateRule struct {
+	scope string
+	limit ratelimit.Limit
+}
+
+// RateLimit ограничивает частоту запросов группы маршрутов. scope разделяет
+// счётчики групп с общим limiter. Превышение лимита - 429 с Retry-After,
+// каждый ответ получает заголовки RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset и RateLimit-Policy. Запрос без ключа (key вернул пустую строку)
+// не ограничивается, при ошибке хранилища запрос пропускается.
+func RateLimit(limiter ratelimit.Limiter, scope string, limit ratelimit.Limit, key ratelimit.KeyFunc, logger *zap.Logger) Middleware {
+	rule := rateRule{scope: scope, limit: limit}
+	return rateLimit(limiter, logger, func(r *http.Request) (rateRule, string) {
+		return rule, key(r)
+	})
+}
+
+// RateLimiter - глобальный rate limiting по блоку middleware.rate_limit:
+// лимиты по префиксу пути (самый длинный префикс), у каждого префикса свои
+// счётчики. Лимиты и источники ключа заменяет Update при перезагрузке
+// конфигурации, алгоритм и хранилище limiter меняются только перезапуском.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	logger  *zap.Logger
+	rules   atomic.Pointer[rateRules]
+}
+
+// rateRules - снимок лимитов и источника ключа из конфигурации
+type rateRules struct {
+	key     ratelimit.KeyFunc
+	matcher *prefixMatcher[rateRule]
+	// auth - лимит до аутентификации, nil - отключён
+	auth *rateRule
+}
+
+// NewRateLimiter возвращает RateLimiter с лимитами из cf
+func NewRateLimiter(limiter ratelimit.Limiter, cf interfaces.ConfigMiddleware, logger *zap.Logger) *RateLimiter {
+	l := &RateLimiter{limiter: limiter, logger: logger}
+	l.Update(cf)
+	return l
+}
+
+// Update применяет лимиты и источники ключа из нового снимка конфигурации.
+// Запросы, уже начавшие обработку, досчитываются по прежним правилам.
+func (l *RateLimiter) Update(cf interfaces.ConfigMiddleware) {
+	// Источники ключа проверены при загрузке конфигурации
+	key, _ := ratelimit.KeysByName(cf.GetRateLimitKeys())
+	routes := cf.GetRateLimitRoutes()
+	rules := make(map[string]rateRule, len(routes))
+	for prefix, limit := range routes {
+		rules[prefix] = rateRule{scope: prefix, limit: limit}
+	}
+	next := &rateRules{
+		key:     key,
+		matcher: newPrefixMatcher(rateRule{scope: "*", limit: cf.GetRateLimitDefault()}, rules),
+	}
+	if limit := cf.GetRateLimitAuth(); limit.Requests > 0 {
+		next.auth = &rateRule{scope: "auth", limit: limit}
+	}
+	l.rules.Store(next)
+}
+
+// Middleware возвращает middleware с актуальными на момент запроса лимитами
+func (l *RateLimiter) Middleware() Middleware {
+	return rateLimit(l.limiter, l.logger, func(r *http.Request) (rateRule, string) {
+		rules := l.rules.Load()
+		return rules.matcher.match(r.URL.Path), rules.key(r)
+	})
+}
+
+// AuthMiddleware возвращает лимит запросов с заголовком Authorization по IP клиента.
+// Ставится до Authenticate: иначе неверные учётные данные отклоняются с 401
+// раньше, чем запрос будет учтён, и подбор ключей и токенов не ограничен.
+func (l *RateLimiter) AuthMiddleware() Middleware {
+	return rateLimit(l.limiter, l.logger, func(r *http.Request) (rateRule, string) {
+		rules := l.rules.Load()
+		if rules.auth == nil || r.Header.Get("Authorization") == "" {
+			return rateRule{}, ""
+		}
+		return *rules.auth, ratelimit.KeyByIP(r)
+	})
+}
+
+// rateLimit - общая часть RateLimit и RateLimiter: match возвращает правило
+// и ключ лимита для запроса
+func rateLimit(limiter ratelimit.Limiter, logger *zap.Logger, match func(*http.Request) (rateRule, string)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rule, k := match(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			res, err := limiter.Allow(r.Context(), rule.scope+"|"+k, rule.limit)
+			if err != nil {
+				logger.Warn("Rate limiter unavailable, request allowed",
+					zap.String("path", r.URL.Path),
+					zap.String("request_id", reqctx.RequestID(r.Context())),
+					zap.Error(err),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.limit.Requests, ceilSeconds(rule.limit.Window)))
+			if !res.Allowed {
+				apperrors.Write(w, r, apperrors.RateLimited(res.RetryAfter))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
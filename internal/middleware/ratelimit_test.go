@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/ratelimit"
+	"go.uber.org/zap"
+)
+
+// rateLimitConfig - блок rate_limit; остальные методы ConfigMiddleware не вызываются
+type rateLimitConfig struct {
+	interfaces.ConfigMiddleware
+	limit  ratelimit.Limit
+	routes map[string]ratelimit.Limit
+	auth   ratelimit.Limit
+}
+
+func (c rateLimitConfig) GetRateLimitKeys() []string                     { return []string{ratelimit.KeyIP} }
+func (c rateLimitConfig) GetRateLimitDefault() ratelimit.Limit           { return c.limit }
+func (c rateLimitConfig) GetRateLimitRoutes() map[string]ratelimit.Limit { return c.routes }
+func (c rateLimitConfig) GetRateLimitAuth() ratelimit.Limit              { return c.auth }
+
+func TestRateLimiterUpdate(t *testing.T) {
+	algorithm, err := ratelimit.AlgorithmByName(ratelimit.AlgorithmSlidingWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := ratelimit.New(algorithm, ratelimit.NewMemoryStore())
+	rl := NewRateLimiter(limiter, rateLimitConfig{limit: ratelimit.Limit{Requests: 1, Window: time.Minute}}, zap.NewNop())
+	h := rl.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(path string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := do("/api/v1/users"); code != http.StatusOK {
+		t.Fatalf("first request: status %d", code)
+	}
+	if code := do("/api/v1/users"); code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status %d, want 429", code)
+	}
+
+	// Новый лимит действует без пересборки стека middleware
+	rl.Update(rateLimitConfig{
+		limit:  ratelimit.Limit{Requests: 1, Window: time.Minute},
+		routes: map[string]ratelimit.Limit{"/api/v1/users": {Requests: 5, Window: time.Minute}},
+	})
+	if code := do("/api/v1/users"); code != http.StatusOK {
+		t.Fatalf("after update: status %d", code)
+	}
+}
+
+// apiKeyAuth принимает единственный ключ "valid" как API ключ 17
+type apiKeyAuth struct{}
+
+func (apiKeyAuth) Scheme() string { return "ApiKey" }
+
+func (apiKeyAuth) Authenticate(_ context.Context, credentials string) (*auth.Principal, error) {
+	if credentials != "valid" {
+		return nil, apperrors.Unauthorized("invalid api key")
+	}
+	return &auth.Principal{Kind: auth.KindAPIKey, Subject: "17"}, nil
+}
+
+func TestRateLimiterAuthBeforeAuthenticate(t *testing.T) {
+	algorithm, err := ratelimit.AlgorithmByName(ratelimit.AlgorithmSlidingWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := NewRateLimiter(ratelimit.New(algorithm, ratelimit.NewMemoryStore()), rateLimitConfig{
+		limit: ratelimit.Limit{Requests: 100, Window: time.Minute},
+		auth:  ratelimit.Limit{Requests: 2, Window: time.Minute},
+	}, zap.NewNop())
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h = rl.Middleware()(h)
+	h = Authenticate(zap.NewNop(), apiKeyAuth{})(h)
+	h = rl.AuthMiddleware()(h)
+
+	do := func(authorization string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Неверные ключи учитываются до аутентификации
+	for i := 0; i < 2; i++ {
+		if code := do("ApiKey guess"); code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: status %d, want 401", i, code)
+		}
+	}
+	if code := do("ApiKey guess"); code != http.StatusTooManyRequests {
+		t.Fatalf("guess after limit: status %d, want 429", code)
+	}
+	if code := do("ApiKey valid"); code != http.StatusTooManyRequests {
+		t.Fatalf("valid key after limit: status %d, want 429", code)
+	}
+	// Запросы без Authorization этот лимит не считает
+	if code := do(""); code != http.StatusOK {
+		t.Fatalf("anonymous request: status %d", code)
+	}
+}
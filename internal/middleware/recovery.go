@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/reqctx"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// Recovery перехватывает панику обработчика, пишет её в журнал со стеком
+// и отвечает 500 (application/problem+json), если ответ ещё не начат
+func Recovery(logger *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// http.ErrAbortHandler - штатное прерывание ответа, его обрабатывает net/http
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				logger.Error("Panic in HTTP handler",
+					zap.Any("panic", rec),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("request_id", reqctx.RequestID(r.Context())),
+					zap.Stack("stack"),
+				)
+				if ww.Status() == 0 {
+					apperrors.Write(ww, r, apperrors.Internal(fmt.Errorf("panic: %v", rec)))
+				}
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/reqctx"
+)
+
+// maxRequestIDLength - входящий идентификатор длиннее заменяется новым
+const maxRequestIDLength = 128
+
+// RequestID берёт идентификатор запроса из заголовка header или создаёт новый,
+// кладёт его в контекст (reqctx.RequestID) и возвращает в том же заголовке ответа
+func RequestID(header string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(header, id)
+			next.ServeHTTP(w, r.WithContext(reqctx.WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID допускает только печатные ASCII символы без пробелов,
+// чтобы идентификатор безопасно попадал в журналы и заголовки
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// readRandom - источник случайных байт идентификатора, подменяется в тестах
+var readRandom = rand.Read
+
+// fallbackSeq - счётчик идентификаторов, созданных без crypto/rand
+var fallbackSeq atomic.Uint64
+
+// newRequestID создаёт 128-битный идентификатор. Если crypto/rand недоступен,
+// идентификатор собирается из времени и счётчика: он предсказуем, но уникален
+// в пределах процесса, а запрос не остаётся без идентификатора.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := readRandom(b); err != nil {
+		binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixNano()))
+		binary.BigEndian.PutUint64(b[8:], fallbackSeq.Add(1))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Timeout ограничивает обработку запроса дедлайном контекста. Обработчик должен
+// уважать r.Context(): если он вернулся после дедлайна, не начав ответ,
+// клиент получает 504. Внутри группы маршрутов дедлайн можно только сократить.
+func Timeout(d time.Duration) Middleware {
+	return RouteTimeouts(d, nil)
+}
+
+// MaxTimeout возвращает самый длинный дедлайн из fallback и routes -
+// по нему сервер выбирает http.Server.WriteTimeout
+func MaxTimeout(fallback time.Duration, routes map[string]time.Duration) time.Duration {
+	longest := fallback
+	for _, d := range routes {
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// RouteTimeouts - Timeout с дедлайнами по префиксу пути (самый длинный префикс)
+func RouteTimeouts(fallback time.Duration, routes map[string]time.Duration) Middleware {
+	matcher := newPrefixMatcher(fallback, routes)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), matcher.match(r.URL.Path))
+			defer cancel()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && ww.Status() == 0 {
+				apperrors.Write(ww, r, apperrors.New(apperrors.KindTimeout, "request timeout"))
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package module
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// BaseMigrationsTable - таблица версий общих миграций из ./migrations
+const BaseMigrationsTable = "schema_migrations"
+
+// MigrationTables - таблицы версий миграций включённых модулей, предоставляются Provide.
+// Нужны проверке миграций, чтобы не пропустить модуль с dirty или непримененной схемой.
+type MigrationTables []string
+
+// MigrationsTable возвращает таблицу версий миграций модуля
+func MigrationsTable(name string) string {
+	return BaseMigrationsTable + "_" + name
+}
+
+// Migrate применяет миграции модулей. У каждого модуля своя таблица версий
+// schema_migrations_<name>, поэтому нумерация миграций модулей независима
+// от общих миграций в ./migrations и друг от друга.
+func Migrate(ctx context.Context, db *sql.DB, modules []Module) error {
+	for _, m := range modules {
+		if m.Migrations == nil {
+			continue
+		}
+		if err := migrateModule(ctx, db, m); err != nil {
+			return fmt.Errorf("module %s migrations: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+func migrateModule(ctx context.Context, db *sql.DB, m Module) error {
+	source, err := iofs.New(m.Migrations, ".")
+	if err != nil {
+		return err
+	}
+
+	// Отдельное соединение из общего пула: mg.Close вернёт его в пул, не закрывая *sql.DB
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{
+		MigrationsTable: MigrationsTable(m.Name),
+	})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	mg, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return err
+	}
+	defer mg.Close()
+
+	if err := mg.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
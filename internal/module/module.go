@@ -0,0 +1,214 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"reflect"
+
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/dig"
+)
+
+// Имена value groups dig, в которые попадают контроллеры, consumers, задачи
+// и health проверки модулей
+const (
+	GroupControllers  = "controllers"
+	GroupConsumers    = "module_consumers"
+	GroupJobs         = "module_jobs"
+	GroupHealthChecks = "health_checks"
+)
+
+// Module - фича, объявленная в одном месте: провайдеры, контроллеры, consumers,
+// крон задачи и миграции. Контейнер, роутер и бинарники загружают модули сами,
+// поэтому добавление фичи не требует правок internal/container.
+type Module struct {
+	// Name - уникальное имя модуля (modules.disabled в конфигурации, таблица версий миграций)
+	Name string
+	// DependsOn - модули, провайдеры которых нужны этому модулю. Отключение
+	// зависимости в modules.disabled - ошибка старта "users requires rbac"
+	// вместо ошибки разрешения графа dig
+	DependsOn []string
+	// Providers - конструкторы для DI контейнера: репозитории, сервисы, use cases, контроллеры
+	Providers []interface{}
+	// Controllers - конструкторы контроллеров, реализующих interfaces.Controller.
+	// Роутер подключает маршруты каждого контроллера (RegisterRoutes) сам
+	Controllers []interface{}
+	// Consumers - конструкторы Consumer (обработчиков очередей RabbitMQ)
+	Consumers []interface{}
+	// Jobs - конструкторы Job (задач для крон скриптов)
+	Jobs []interface{}
+	// HealthChecks - конструкторы interfaces.HealthCheck для /livez, /readyz и /startupz
+	HealthChecks []interface{}
+	// Migrations - SQL миграции модуля (обычно embed.FS), применяются
+	// с отдельной таблицей версий schema_migrations_<name>
+	Migrations fs.FS
+}
+
+// Provider регистрирует конструкторы в DI контейнере (*dig.Container или обёртка над ним)
+type Provider interface {
+	Provide(constructor interface{}, opts ...dig.ProvideOption) error
+}
+
+// Consumer - обработчик сообщений очереди RabbitMQ
+type Consumer struct {
+	Queue  string
+	Handle func(ctx context.Context, body []byte) error
+}
+
+// Job - крон задача модуля
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// ControllersIn - параметр для Invoke, собирающий контроллеры всех включённых модулей
+type ControllersIn struct {
+	dig.In
+	Controllers []interfaces.Controller `group:"controllers"`
+}
+
+// ConsumersIn - параметр для Invoke, собирающий consumers всех включённых модулей
+type ConsumersIn struct {
+	dig.In
+	Consumers []Consumer `group:"module_consumers"`
+}
+
+// JobsIn - параметр для Invoke, собирающий задачи всех включённых модулей
+type JobsIn struct {
+	dig.In
+	Jobs []Job `group:"module_jobs"`
+}
+
+// HealthChecksIn - параметр конструктора, собирающий health проверки
+// инфраструктуры и всех включённых модулей
+type HealthChecksIn struct {
+	dig.In
+	Checks []interfaces.HealthCheck `group:"health_checks"`
+}
+
+// Provide регистрирует включённые модули в контейнере и возвращает их.
+// Таблицы версий их миграций доступны в контейнере как MigrationTables.
+// Модули из disabled пропускаются. Повторяющиеся имена модулей, типы,
+// которые предоставляют сразу несколько модулей, и включённые модули
+// с отключённой или неизвестной зависимостью (DependsOn) возвращаются как ошибка.
+func Provide(c Provider, modules []Module, disabled []string) ([]Module, error) {
+	skip := make(map[string]bool, len(disabled))
+	for _, name := range disabled {
+		skip[name] = true
+	}
+	if err := checkDependencies(modules, skip); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(modules))
+	providedBy := make(map[reflect.Type]string)
+	var enabled []Module
+
+	for _, m := range modules {
+		if m.Name == "" {
+			return nil, fmt.Errorf("module without name")
+		}
+		if seen[m.Name] {
+			return nil, fmt.Errorf("module %s registered twice", m.Name)
+		}
+		seen[m.Name] = true
+
+		if skip[m.Name] {
+			continue
+		}
+
+		for _, ctor := range m.Providers {
+			for _, t := range outputTypes(ctor) {
+				if owner, ok := providedBy[t]; ok {
+					return nil, fmt.Errorf("module %s: %s is already provided by module %s", m.Name, t, owner)
+				}
+				providedBy[t] = m.Name
+			}
+			if err := c.Provide(ctor); err != nil {
+				return nil, fmt.Errorf("module %s: %w", m.Name, err)
+			}
+		}
+
+		for _, ctor := range m.Controllers {
+			if err := c.Provide(ctor, dig.Group(GroupControllers), dig.As(new(interfaces.Controller))); err != nil {
+				return nil, fmt.Errorf("module %s controller: %w", m.Name, err)
+			}
+		}
+		for _, ctor := range m.Consumers {
+			if err := c.Provide(ctor, dig.Group(GroupConsumers)); err != nil {
+				return nil, fmt.Errorf("module %s consumer: %w", m.Name, err)
+			}
+		}
+		for _, ctor := range m.Jobs {
+			if err := c.Provide(ctor, dig.Group(GroupJobs)); err != nil {
+				return nil, fmt.Errorf("module %s job: %w", m.Name, err)
+			}
+		}
+		for _, ctor := range m.HealthChecks {
+			if err := c.Provide(ctor, dig.Group(GroupHealthChecks)); err != nil {
+				return nil, fmt.Errorf("module %s health check: %w", m.Name, err)
+			}
+		}
+
+		enabled = append(enabled, m)
+	}
+
+	for _, name := range disabled {
+		if !seen[name] {
+			return nil, fmt.Errorf("modules.disabled: unknown module %s", name)
+		}
+	}
+
+	var tables MigrationTables
+	for _, m := range enabled {
+		if m.Migrations != nil {
+			tables = append(tables, MigrationsTable(m.Name))
+		}
+	}
+	if err := c.Provide(func() MigrationTables { return tables }); err != nil {
+		return nil, fmt.Errorf("migration tables: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// checkDependencies проверяет, что зависимости включённых модулей
+// зарегистрированы и не отключены
+func checkDependencies(modules []Module, skip map[string]bool) error {
+	known := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		known[m.Name] = true
+	}
+	for _, m := range modules {
+		if skip[m.Name] {
+			continue
+		}
+		for _, dep := range m.DependsOn {
+			switch {
+			case !known[dep]:
+				return fmt.Errorf("module %s requires unknown module %s", m.Name, dep)
+			case skip[dep]:
+				return fmt.Errorf("module %s requires %s, which is disabled in modules.disabled", m.Name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// outputTypes возвращает типы результатов конструктора, кроме error
+func outputTypes(ctor interface{}) []reflect.Type {
+	t := reflect.TypeOf(ctor)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil
+	}
+	var types []reflect.Type
+	for i := 0; i < t.NumOut(); i++ {
+		if out := t.Out(i); out != errorType {
+			types = append(types, out)
+		}
+	}
+	return types
+}
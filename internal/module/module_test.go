@@ -0,0 +1,40 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+)
+
+type featureService struct{}
+
+func TestProvideDependsOn(t *testing.T) {
+	modules := []Module{
+		{Name: "rbac", Providers: []interface{}{func() *featureService { return &featureService{} }}},
+		{Name: "users", DependsOn: []string{"rbac"}},
+	}
+
+	if _, err := Provide(dig.New(), modules, nil); err != nil {
+		t.Fatalf("all modules enabled: %v", err)
+	}
+
+	_, err := Provide(dig.New(), modules, []string{"rbac"})
+	if err == nil || !strings.Contains(err.Error(), "module users requires rbac") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Отключены оба модуля - зависимость не нужна
+	if _, err := Provide(dig.New(), modules, []string{"rbac", "users"}); err != nil {
+		t.Fatalf("both modules disabled: %v", err)
+	}
+}
+
+func TestProvideUnknownDependency(t *testing.T) {
+	modules := []Module{{Name: "users", DependsOn: []string{"rbca"}}}
+
+	_, err := Provide(dig.New(), modules, nil)
+	if err == nil || !strings.Contains(err.Error(), "requires unknown module rbca") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
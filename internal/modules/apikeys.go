@@ -0,0 +1,35 @@
+package modules
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/repositories"
+	"github.com/SmirnovND/gobase/internal/services"
+)
+
+//go:embed migrations/apikeys/*.sql
+var apiKeysMigrations embed.FS
+
+func init() {
+	migrations, err := fs.Sub(apiKeysMigrations, "migrations/apikeys")
+	if err != nil {
+		panic(err)
+	}
+	register(module.Module{
+		Name: "apikeys",
+		// Схему ApiKey подключает роутер при auth.api_key.enabled,
+		// API управления ключами требует модуль rbac (interfaces.Authorizer)
+		DependsOn: []string{"rbac"},
+		Providers: []interface{}{
+			repositories.NewAPIKeyRepository,
+			services.NewAPIKeyService,
+		},
+		Controllers: []interface{}{
+			controllers.NewAPIKeyController,
+		},
+		Migrations: migrations,
+	})
+}
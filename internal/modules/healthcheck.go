@@ -0,0 +1,100 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/infra"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/repositories"
+	"github.com/SmirnovND/gobase/internal/services"
+	"go.uber.org/zap"
+	"sync"
+)
+
+func init() {
+	register(module.Module{
+		Name: "healthcheck",
+		// dig смотрит на сигнатуры конструкторов и сам связывает
+		// repository -> service -> controller через интерфейсы
+		Providers: []interface{}{
+			repositories.NewHealthcheckRepository,
+			newHealthcheckService,
+		},
+		// Маршруты объявляет сам контроллер (RegisterRoutes)
+		Controllers: []interface{}{
+			controllers.NewHealthcheckController,
+		},
+		HealthChecks: []interface{}{
+			postgresCheck,
+			migrationsCheck,
+		},
+	})
+}
+
+// newHealthcheckService - сервис получает проверки инфраструктуры и всех модулей
+// из value group; фоновое обновление результатов работает между Start и остановкой контейнера
+func newHealthcheckService(in module.HealthChecksIn, cf interfaces.ConfigCommon, lc interfaces.Lifecycle, logger *zap.Logger) interfaces.HealthcheckService {
+	svc := services.NewHealthcheckService(in.Checks, services.HealthcheckOptions{
+		CheckTimeout:    cf.GetHealthCheckTimeout(),
+		RefreshInterval: cf.GetHealthRefreshInterval(),
+		MaxAgeIntervals: cf.GetHealthMaxAgeIntervals(),
+		FreshInterval:   cf.GetHealthFreshInterval(),
+	}, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var running sync.WaitGroup
+	lc.Append(interfaces.Hook{
+		Name: "healthcheck.refresh",
+		OnStart: func(context.Context) error {
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				svc.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			// Дедлайн остановки соблюдает lifecycle: зависшие проверки ограничены своими таймаутами
+			cancel()
+			running.Wait()
+			return nil
+		},
+	})
+	return svc
+}
+
+// postgresCheck - подключение к PostgreSQL через репозиторий: с моком
+// HealthcheckRepository проверка не открывает соединение с БД
+func postgresCheck(repo interfaces.HealthcheckRepository, cf interfaces.ConfigCommon, status interfaces.DependencyStatus) interfaces.HealthCheck {
+	return infra.PostgresCheck(repo.Ping, cf.GetDBRequired(), status)
+}
+
+// migrationsCheck - startup проба: общие миграции и миграции каждого включённого модуля
+// применены и не остались в состоянии dirty.
+// Обязательна вместе с БД: при db.required: false миграции могли быть пропущены при старте.
+func migrationsCheck(repo interfaces.HealthcheckRepository, cf interfaces.ConfigCommon, tables module.MigrationTables) interfaces.HealthCheck {
+	all := append([]string{module.BaseMigrationsTable}, tables...)
+	return interfaces.HealthCheck{
+		Name:     "migrations",
+		Probes:   []domain.Probe{domain.ProbeStartup},
+		Required: cf.GetDBRequired(),
+		Check: func(ctx context.Context) error {
+			var errs []error
+			for _, table := range all {
+				version, dirty, err := repo.MigrationVersion(ctx, table)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", table, err))
+					continue
+				}
+				if dirty {
+					errs = append(errs, fmt.Errorf("%s: schema version %d is dirty", table, version))
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
@@ -0,0 +1,20 @@
+-- API ключи модуля apikeys: хранится SHA-256 ключа, prefix - открытое начало ключа.
+-- previous_hash - ключ до ротации, принимается до previous_expires_at
+CREATE TABLE IF NOT EXISTS api_keys (
+    id                  BIGSERIAL PRIMARY KEY,
+    name                TEXT NOT NULL,
+    prefix              TEXT NOT NULL,
+    hash                TEXT NOT NULL UNIQUE,
+    previous_hash       TEXT,
+    previous_expires_at TIMESTAMPTZ,
+    scopes              TEXT[] NOT NULL DEFAULT '{}',
+    created_by          TEXT NOT NULL DEFAULT '',
+    expires_at          TIMESTAMPTZ,
+    last_used_at        TIMESTAMPTZ,
+    rotated_at          TIMESTAMPTZ,
+    revoked_at          TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_name ON api_keys (name);
+CREATE INDEX IF NOT EXISTS idx_api_keys_previous_hash ON api_keys (previous_hash) WHERE previous_hash IS NOT NULL;
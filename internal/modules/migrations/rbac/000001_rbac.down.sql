@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rbac_role_bindings;
+DROP TABLE IF EXISTS rbac_permissions;
+DROP TABLE IF EXISTS rbac_roles;
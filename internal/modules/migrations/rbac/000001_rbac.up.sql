@@ -0,0 +1,27 @@
+-- Роли, разрешения и привязки ролей к субъектам модуля rbac
+CREATE TABLE IF NOT EXISTS rbac_roles (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS rbac_permissions (
+    id       BIGSERIAL PRIMARY KEY,
+    role_id  BIGINT NOT NULL REFERENCES rbac_roles (id) ON DELETE CASCADE,
+    action   TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    UNIQUE (role_id, action, resource)
+);
+
+-- subject_kind - auth.KindUser или auth.KindAPIKey, subject - Principal.Subject
+CREATE TABLE IF NOT EXISTS rbac_role_bindings (
+    id           BIGSERIAL PRIMARY KEY,
+    subject_kind TEXT NOT NULL,
+    subject      TEXT NOT NULL,
+    role_id      BIGINT NOT NULL REFERENCES rbac_roles (id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (subject_kind, subject, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rbac_role_bindings_role_id ON rbac_role_bindings (role_id);
@@ -0,0 +1,12 @@
+-- Пользователи модуля users
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- email хранится в нижнем регистре (UserService), индекс страхует от дублей в обход сервиса
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (lower(email));
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
@@ -0,0 +1,18 @@
+// Package modules - реестр фич проекта. Каждая фича объявляет свой module.Module
+// в отдельном файле этого пакета и регистрирует его в init(), поэтому
+// добавление фичи не требует правок контейнера, роутера и бинарников.
+package modules
+
+import "github.com/SmirnovND/gobase/internal/module"
+
+var registry []module.Module
+
+// register добавляет модуль в реестр (вызывается из init() файла фичи)
+func register(m module.Module) {
+	registry = append(registry, m)
+}
+
+// All возвращает все зарегистрированные модули в порядке регистрации
+func All() []module.Module {
+	return append([]module.Module(nil), registry...)
+}
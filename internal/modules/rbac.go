@@ -0,0 +1,43 @@
+package modules
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/repositories"
+	"github.com/SmirnovND/gobase/internal/services"
+)
+
+//go:embed migrations/rbac/*.sql
+var rbacMigrations embed.FS
+
+func init() {
+	migrations, err := fs.Sub(rbacMigrations, "migrations/rbac")
+	if err != nil {
+		panic(err)
+	}
+	register(module.Module{
+		Name: "rbac",
+		Providers: []interface{}{
+			repositories.NewRBACRepository,
+			newRBACService,
+			// Use cases и middleware.Authorize зависят только от проверки прав
+			func(s interfaces.RBACService) interfaces.Authorizer { return s },
+		},
+		Controllers: []interface{}{
+			controllers.NewRBACController,
+		},
+		Migrations: migrations,
+	})
+}
+
+// newRBACService - сервис RBAC с параметрами блока rbac
+func newRBACService(repo interfaces.RBACRepository, cf interfaces.ConfigCommon) interfaces.RBACService {
+	return services.NewRBACService(repo, services.RBACOptions{
+		CacheTTL:        cf.GetRBACCacheTTL(),
+		TrustTokenRoles: cf.GetRBACTrustTokenRoles(),
+	})
+}
@@ -0,0 +1,39 @@
+package modules
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/SmirnovND/gobase/internal/controllers"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/repositories"
+	"github.com/SmirnovND/gobase/internal/services"
+	"github.com/SmirnovND/gobase/internal/usecases"
+)
+
+//go:embed migrations/users/*.sql
+var usersMigrations embed.FS
+
+// Модуль users - эталонный срез фичи: миграция, repository (с Tx методами),
+// service, use case с транзакцией и проверкой прав, REST контроллер
+func init() {
+	migrations, err := fs.Sub(usersMigrations, "migrations/users")
+	if err != nil {
+		panic(err)
+	}
+	register(module.Module{
+		Name: "users",
+		// repository -> service -> use case -> controller; права проверяет
+		// use case через interfaces.Authorizer модуля rbac
+		DependsOn: []string{"rbac"},
+		Providers: []interface{}{
+			repositories.NewUserRepository,
+			services.NewUserService,
+			usecases.NewUserUsecase,
+		},
+		Controllers: []interface{}{
+			controllers.NewUserController,
+		},
+		Migrations: migrations,
+	})
+}
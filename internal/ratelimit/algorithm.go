@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Названия алгоритмов в конфигурации
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
+// AlgorithmByName возвращает алгоритм по названию из конфигурации
+func AlgorithmByName(name string) (Algorithm, error) {
+	switch name {
+	case AlgorithmTokenBucket:
+		return TokenBucket{}, nil
+	case AlgorithmSlidingWindow:
+		return SlidingWindow{}, nil
+	}
+	return nil, fmt.Errorf("unknown rate limit algorithm %q", name)
+}
+
+// TokenBucket - ведро ёмкостью Burst, пополняется на Requests токенов за Window.
+// Допускает короткие всплески после простоя, средняя частота - не выше лимита.
+// Состояние: Value - токены, At - время последнего пополнения.
+type TokenBucket struct{}
+
+func (TokenBucket) Take(s *State, limit Limit, now time.Time) Result {
+	capacity := float64(burst(limit))
+	rate := float64(limit.Requests) / limit.Window.Seconds()
+
+	tokens := capacity
+	if !s.At.IsZero() {
+		elapsed := now.Sub(s.At).Seconds()
+		tokens = math.Min(capacity, s.Value+math.Max(elapsed, 0)*rate)
+	}
+
+	res := Result{Limit: burst(limit)}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	s.Value, s.At = tokens, now
+
+	res.Remaining = int(tokens)
+	res.Reset = seconds((capacity - tokens) / rate)
+	return res
+}
+
+func (TokenBucket) TTL(limit Limit) time.Duration {
+	// За это время ведро наполняется, и состояние не отличается от нового
+	return time.Duration(float64(limit.Window) * float64(burst(limit)) / float64(limit.Requests))
+}
+
+// SlidingWindow - счётчики текущего и предыдущего окна фиксированной длины.
+// Число запросов за последние Window оценивается как текущий счётчик плюс
+// доля предыдущего, пропорциональная перекрытию: всплеск на границе окон
+// не удваивает лимит. Состояние: Value и Prev - счётчики, At - начало текущего окна.
+type SlidingWindow struct{}
+
+func (SlidingWindow) Take(s *State, limit Limit, now time.Time) Result {
+	start := now.Truncate(limit.Window)
+	switch {
+	case s.At.Equal(start):
+	case s.At.Equal(start.Add(-limit.Window)):
+		s.Prev, s.Value = s.Value, 0
+	default:
+		s.Prev, s.Value = 0, 0
+	}
+	s.At = start
+
+	elapsed := now.Sub(start)
+	weight := 1 - elapsed.Seconds()/limit.Window.Seconds()
+	quota := float64(limit.Requests)
+	estimate := s.Prev*weight + s.Value
+
+	res := Result{Limit: limit.Requests}
+	if estimate+1 <= quota {
+		s.Value++
+		estimate++
+		res.Allowed = true
+	} else {
+		res.RetryAfter = slidingRetryAfter(s, quota, limit.Window, elapsed)
+	}
+	res.Remaining = int(math.Max(0, math.Floor(quota-estimate)))
+	// Квота полностью восстановится, когда запросы текущего окна перестанут учитываться
+	switch {
+	case s.Value > 0:
+		res.Reset = 2*limit.Window - elapsed
+	case s.Prev > 0:
+		res.Reset = limit.Window - elapsed
+	}
+	return res
+}
+
+// slidingRetryAfter - когда оценка опустится настолько, что запрос поместится в лимит
+func slidingRetryAfter(s *State, quota float64, window, elapsed time.Duration) time.Duration {
+	if s.Value+1 <= quota && s.Prev > 0 {
+		// Достаточно, чтобы вклад предыдущего окна уменьшился в текущем
+		weight := (quota - 1 - s.Value) / s.Prev
+		return seconds((1-weight)*window.Seconds() - elapsed.Seconds())
+	}
+	// Текущее окно заполнено: ждём следующего, где оно станет предыдущим
+	weight := (quota - 1) / s.Value
+	return window - elapsed + seconds((1-weight)*window.Seconds())
+}
+
+func (SlidingWindow) TTL(limit Limit) time.Duration {
+	return 2 * limit.Window
+}
+
+func burst(limit Limit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return limit.Requests
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/reqctx"
+)
+
+// KeyFunc возвращает ключ лимита для запроса или пустую строку, если
+// источник ключа к запросу неприменим
+type KeyFunc func(r *http.Request) string
+
+// Названия источников ключа в конфигурации
+const (
+	KeyIP     = "ip"
+	KeyAPIKey = "api_key"
+	KeyUser   = "user"
+)
+
+// KeyByIP - адрес клиента (middleware RealIP), иначе RemoteAddr
+func KeyByIP(r *http.Request) string {
+	ip := reqctx.ClientIP(r.Context())
+	if ip == "" {
+		ip = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return "ip:" + ip
+}
+
+// KeyByAPIKey - аутентифицированный API ключ (субъект вида auth.KindAPIKey).
+// Заголовок Authorization не читается: непроверенный ключ не даёт отдельного
+// счётчика, такие запросы ограничивает лимит до аутентификации (RateLimiter.AuthMiddleware).
+func KeyByAPIKey(r *http.Request) string {
+	if p, ok := auth.PrincipalFrom(r.Context()); ok && p.Kind == auth.KindAPIKey {
+		return "apikey:" + p.Subject
+	}
+	return ""
+}
+
+// KeyByUser - аутентифицированный субъект (reqctx.Subject). Аутентификация
+// должна выполняться раньше rate limiting.
+func KeyByUser(r *http.Request) string {
+	if subject := reqctx.Subject(r.Context()); subject != "" {
+		return "user:" + subject
+	}
+	return ""
+}
+
+// FirstKey возвращает первый непустой ключ из keys: например, пользователь,
+// затем API ключ, затем IP
+func FirstKey(keys ...KeyFunc) KeyFunc {
+	return func(r *http.Request) string {
+		for _, key := range keys {
+			if k := key(r); k != "" {
+				return k
+			}
+		}
+		return ""
+	}
+}
+
+// KeysByName - FirstKey из источников ключа по названиям из конфигурации
+func KeysByName(names []string) (KeyFunc, error) {
+	keys := make([]KeyFunc, 0, len(names))
+	for _, name := range names {
+		key, err := KeyByName(name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return FirstKey(keys...), nil
+}
+
+// KeyByName возвращает источник ключа по названию из конфигурации
+func KeyByName(name string) (KeyFunc, error) {
+	switch name {
+	case KeyIP:
+		return KeyByIP, nil
+	case KeyAPIKey:
+		return KeyByAPIKey, nil
+	case KeyUser:
+		return KeyByUser, nil
+	}
+	return nil, fmt.Errorf("unknown rate limit key %q", name)
+}
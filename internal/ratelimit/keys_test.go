@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+)
+
+func TestKeyByAPIKey(t *testing.T) {
+	// Непроверенный ключ из заголовка не даёт ключа лимита
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "ApiKey secret")
+	if key := KeyByAPIKey(req); key != "" {
+		t.Fatalf("unauthenticated key = %q, want empty", key)
+	}
+
+	user := req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Kind: auth.KindUser, Subject: "17"}))
+	if key := KeyByAPIKey(user); key != "" {
+		t.Fatalf("user key = %q, want empty", key)
+	}
+
+	apiKey := req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Kind: auth.KindAPIKey, Subject: "17"}))
+	if key := KeyByAPIKey(apiKey); key != "apikey:17" {
+		t.Fatalf("api key = %q, want apikey:17", key)
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Названия хранилищ в конфигурации
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// sweepInterval - как часто хранилища удаляют истёкшие ключи
+const sweepInterval = time.Minute
+
+// memoryStore - состояние в памяти процесса. У каждой реплики свои счётчики,
+// поэтому для нескольких экземпляров сервиса нужен NewPostgresStore.
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+// NewMemoryStore возвращает хранилище в памяти процесса. Истёкшие ключи
+// удаляются при обращениях не чаще раза в минуту, фоновая горутина не нужна.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (m *memoryStore) Update(_ context.Context, key string, ttl time.Duration, fn func(s *State, now time.Time)) error {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for k, e := range m.entries {
+			if !now.Before(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	var state State
+	if e, ok := m.entries[key]; ok && now.Before(e.expires) {
+		state = e.state
+	}
+	fn(&state, now)
+	m.entries[key] = memoryEntry{state: state, expires: now.Add(ttl)}
+	return nil
+}
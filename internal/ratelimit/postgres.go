@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// postgresStore - состояние в таблице rate_limits (migrations/000002_rate_limits).
+// Ключ блокируется строкой (SELECT ... FOR UPDATE) на время транзакции,
+// время берётся из БД, поэтому расхождение часов реплик не влияет на лимиты.
+type postgresStore struct {
+	db        *sqlx.DB
+	lastSweep atomic.Int64
+}
+
+// NewPostgresStore возвращает хранилище, общее для всех реплик сервиса
+func NewPostgresStore(db *sqlx.DB) Store {
+	return &postgresStore{db: db}
+}
+
+type stateRow struct {
+	Value float64   `db:"value"`
+	Prev  float64   `db:"prev"`
+	At    time.Time `db:"at"`
+	Live  bool      `db:"live"`
+	Now   time.Time `db:"now"`
+}
+
+func (p *postgresStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(s *State, now time.Time)) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Строка нужна до SELECT FOR UPDATE: иначе два первых запроса ключа не заблокируют друг друга
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO rate_limits (key, value, prev, at, expires_at)
+		VALUES ($1, 0, 0, 'epoch', now())
+		ON CONFLICT (key) DO NOTHING`, key); err != nil {
+		return err
+	}
+
+	var row stateRow
+	if err := tx.GetContext(ctx, &row, `
+		SELECT value, prev, at, expires_at > now() AS live, now() AS now
+		FROM rate_limits WHERE key = $1 FOR UPDATE`, key); err != nil {
+		return err
+	}
+
+	var state State
+	if row.Live {
+		state = State{Value: row.Value, Prev: row.Prev, At: row.At}
+	}
+	fn(&state, row.Now)
+
+	at := state.At
+	if at.IsZero() {
+		at = time.Unix(0, 0)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE rate_limits SET value = $2, prev = $3, at = $4, expires_at = $5
+		WHERE key = $1`, key, state.Value, state.Prev, at, row.Now.Add(ttl)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	p.sweep(ctx, row.Now)
+	return nil
+}
+
+// sweep удаляет истёкшие ключи не чаще sweepInterval на реплику.
+// Ошибка не влияет на решение по запросу: очистка повторится позже.
+func (p *postgresStore) sweep(ctx context.Context, now time.Time) {
+	last := p.lastSweep.Load()
+	if now.Sub(time.Unix(0, last)) < sweepInterval || !p.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	_, _ = p.db.ExecContext(ctx, "DELETE FROM rate_limits WHERE expires_at <= now()")
+}
@@ -0,0 +1,85 @@
+// Package ratelimit - ограничение частоты запросов: алгоритмы token bucket
+// и sliding window поверх хранилища состояния. NewMemoryStore подходит для
+// одного экземпляра сервиса, NewPostgresStore - для нескольких реплик с общей БД.
+// HTTP middleware - internal/middleware.RateLimit.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limit - не больше Requests запросов за Window. Burst - ёмкость token bucket
+// (сколько запросов можно сделать подряд после простоя), 0 - равна Requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// Result - решение по запросу и значения для заголовков RateLimit-*
+type Result struct {
+	Allowed bool
+	// Limit - размер квоты
+	Limit int
+	// Remaining - сколько запросов осталось в квоте
+	Remaining int
+	// Reset - через сколько квота восстановится полностью
+	Reset time.Duration
+	// RetryAfter - через сколько повторить отклонённый запрос
+	RetryAfter time.Duration
+}
+
+// State - состояние лимита по ключу. Смысл полей задаёт алгоритм,
+// нулевое состояние - ключ без истории.
+type State struct {
+	Value float64
+	Prev  float64
+	At    time.Time
+}
+
+// Algorithm - алгоритм лимита
+type Algorithm interface {
+	// Take учитывает запрос в момент now и изменяет состояние
+	Take(s *State, limit Limit, now time.Time) Result
+	// TTL - через сколько неиспользуемое состояние можно удалить
+	TTL(limit Limit) time.Duration
+}
+
+// Store хранит состояние лимитов. Update выполняет fn атомарно для ключа:
+// fn получает текущее состояние (нулевое для нового или истёкшего ключа)
+// и время хранилища, изменённое состояние хранится ttl.
+type Store interface {
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(s *State, now time.Time)) error
+}
+
+// Limiter проверяет запросы по ключу
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type limiter struct {
+	algorithm Algorithm
+	store     Store
+}
+
+// New возвращает Limiter с алгоритмом algorithm и хранилищем store
+func New(algorithm Algorithm, store Store) Limiter {
+	return &limiter{algorithm: algorithm, store: store}
+}
+
+func (l *limiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	var res Result
+	err := l.store.Update(ctx, key, l.algorithm.TTL(limit), func(s *State, now time.Time) {
+		res = l.algorithm.Take(s, limit, now)
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: %w", err)
+	}
+	return res, nil
+}
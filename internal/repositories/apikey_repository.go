@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+const apiKeyColumns = "id, name, prefix, hash, previous_hash, previous_expires_at, scopes, created_by, expires_at, last_used_at, rotated_at, revoked_at, created_at"
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) interfaces.APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) List(ctx context.Context, filter domain.APIKeyFilter) ([]domain.APIKey, int64, error) {
+	var conds []string
+	var args []interface{}
+	if filter.Name != "" {
+		args = append(args, filter.Name)
+		conds = append(conds, "name = $"+strconv.Itoa(len(args)))
+	}
+	if !filter.WithRevoked {
+		conds = append(conds, "revoked_at IS NULL")
+	}
+	from := " FROM api_keys"
+	if len(conds) > 0 {
+		from += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT count(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + apiKeyColumns + from + " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+	var keys []domain.APIKey
+	if err := r.db.SelectContext(ctx, &keys, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return keys, total, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	return r.get(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id)
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return r.get(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE hash = $1 OR previous_hash = $1 LIMIT 1", hash)
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	return r.db.GetContext(ctx, key, `
+		INSERT INTO api_keys (name, prefix, hash, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+apiKeyColumns,
+		key.Name, key.Prefix, key.Hash, key.Scopes, key.CreatedBy, key.ExpiresAt)
+}
+
+func (r *apiKeyRepository) Rotate(ctx context.Context, id int64, prefix, hash string, gracePeriod time.Duration) (*domain.APIKey, error) {
+	return r.get(ctx, `
+		UPDATE api_keys SET
+			prefix = $2,
+			hash = $3,
+			previous_hash = CASE WHEN $4::float8 > 0 THEN hash END,
+			previous_expires_at = CASE WHEN $4::float8 > 0 THEN now() + make_interval(secs => $4::float8) END,
+			rotated_at = now()
+		WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+		RETURNING `+apiKeyColumns,
+		id, prefix, hash, gracePeriod.Seconds())
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1", id)
+	return affected(res, err, domain.ErrAPIKeyNotFound)
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = $2 WHERE id = $1", id, at)
+	return err
+}
+
+func (r *apiKeyRepository) get(ctx context.Context, query string, args ...interface{}) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.db.GetContext(ctx, &key, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
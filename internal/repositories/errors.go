@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Коды ошибок PostgreSQL, которые репозитории переводят в ошибки фич
+const pgUniqueViolation = "23505"
+
+// isUniqueViolation сообщает, нарушено ли ограничение уникальности
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
+
+// escapeLike экранирует % и _ в значении для LIKE/ILIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,156 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeQuery - выполненный запрос: текст, аргументы и был ли он внутри транзакции
+type fakeQuery struct {
+	query string
+	args  []interface{}
+	inTx  bool
+}
+
+// fakeResult - ответ на запрос: строки для SELECT/RETURNING или число затронутых строк
+type fakeResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// fakeDB - database/sql драйвер в памяти: записывает запросы и отвечает через respond.
+// Позволяет проверять SQL репозиториев без PostgreSQL.
+type fakeDB struct {
+	respond func(query string) fakeResult
+
+	mu      sync.Mutex
+	queries []fakeQuery
+	events  []string
+}
+
+// newFakeDB возвращает *sqlx.DB поверх fakeDB с плейсхолдерами PostgreSQL
+func newFakeDB(respond func(query string) fakeResult) (*sqlx.DB, *fakeDB) {
+	f := &fakeDB{respond: respond}
+	return sqlx.NewDb(sql.OpenDB(f), "postgres"), f
+}
+
+func (f *fakeDB) Connect(ctx context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                            { return fakeDriver{} }
+
+// Queries возвращает выполненные запросы по порядку
+func (f *fakeDB) Queries() []fakeQuery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeQuery(nil), f.queries...)
+}
+
+// Events - BEGIN, COMMIT и ROLLBACK по порядку
+func (f *fakeDB) Events() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.events...)
+}
+
+func (f *fakeDB) run(c *fakeConn, query string, args []driver.NamedValue) fakeResult {
+	values := make([]interface{}, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	f.mu.Lock()
+	f.queries = append(f.queries, fakeQuery{query: query, args: values, inTx: c.inTx})
+	f.mu.Unlock()
+	return f.respond(query)
+}
+
+func (f *fakeDB) event(name string) {
+	f.mu.Lock()
+	f.events = append(f.events, name)
+	f.mu.Unlock()
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakedb: use sql.OpenDB")
+}
+
+type fakeConn struct {
+	db   *fakeDB
+	inTx bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakedb: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.inTx = true
+	c.db.event("BEGIN")
+	return &fakeTx{conn: c}, nil
+}
+
+// CheckNamedValue принимает аргументы как есть, чтобы тест видел исходные значения
+func (c *fakeConn) CheckNamedValue(nv *driver.NamedValue) error { return nil }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res := c.db.run(c, query, args)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &fakeRows{columns: res.columns, rows: res.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res := c.db.run(c, query, args)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return driver.RowsAffected(res.affected), nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (t *fakeTx) Commit() error {
+	t.conn.inTx = false
+	t.conn.db.event("COMMIT")
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.conn.inTx = false
+	t.conn.db.event("ROLLBACK")
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
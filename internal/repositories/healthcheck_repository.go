@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"github.com/SmirnovND/gobase/internal/interfaces"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type healthcheckRepository struct {
@@ -20,3 +23,21 @@ func NewHealthcheckRepository(db *sqlx.DB) interfaces.HealthcheckRepository {
 func (r *healthcheckRepository) Ping(ctx context.Context) error {
 	return r.db.PingContext(ctx)
 }
+
+// MigrationVersion возвращает версию схемы из таблицы версий table
+// (schema_migrations или schema_migrations_<module>).
+// Пустая таблица (миграций ещё нет) - версия 0.
+func (r *healthcheckRepository) MigrationVersion(ctx context.Context, table string) (uint, bool, error) {
+	var row struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	err := r.db.GetContext(ctx, &row, "SELECT version, dirty FROM "+pq.QuoteIdentifier(table)+" LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return row.Version, row.Dirty, nil
+}
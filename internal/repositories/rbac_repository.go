@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type rbacRepository struct {
+	db *sqlx.DB
+}
+
+func NewRBACRepository(db *sqlx.DB) interfaces.RBACRepository {
+	return &rbacRepository{
+		db: db,
+	}
+}
+
+func (r *rbacRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	var roles []domain.Role
+	if err := r.db.SelectContext(ctx, &roles, "SELECT id, name, description, created_at FROM rbac_roles ORDER BY name"); err != nil {
+		return nil, err
+	}
+	var permissions []domain.Permission
+	if err := r.db.SelectContext(ctx, &permissions, "SELECT id, role_id, action, resource FROM rbac_permissions ORDER BY id"); err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[int64][]domain.Permission, len(roles))
+	for _, p := range permissions {
+		byRole[p.RoleID] = append(byRole[p.RoleID], p)
+	}
+	for i := range roles {
+		roles[i].Permissions = nonNil(byRole[roles[i].ID])
+	}
+	return roles, nil
+}
+
+func (r *rbacRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	err := r.db.GetContext(ctx, &role, "SELECT id, name, description, created_at FROM rbac_roles WHERE name = $1", name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.SelectContext(ctx, &role.Permissions, "SELECT id, role_id, action, resource FROM rbac_permissions WHERE role_id = $1 ORDER BY id", role.ID); err != nil {
+		return nil, err
+	}
+	role.Permissions = nonNil(role.Permissions)
+	return &role, nil
+}
+
+func (r *rbacRepository) CreateRole(ctx context.Context, role *domain.Role) error {
+	err := r.db.GetContext(ctx, role,
+		"INSERT INTO rbac_roles (name, description) VALUES ($1, $2) RETURNING id, name, description, created_at",
+		role.Name, role.Description)
+	if isUniqueViolation(err) {
+		return domain.ErrRoleExists
+	}
+	role.Permissions = nonNil(role.Permissions)
+	return err
+}
+
+func (r *rbacRepository) DeleteRole(ctx context.Context, name string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM rbac_roles WHERE name = $1", name)
+	return affected(res, err, domain.ErrRoleNotFound)
+}
+
+func (r *rbacRepository) CreatePermission(ctx context.Context, role string, p *domain.Permission) error {
+	err := r.db.GetContext(ctx, p, `
+		INSERT INTO rbac_permissions (role_id, action, resource)
+		SELECT id, $2, $3 FROM rbac_roles WHERE name = $1
+		RETURNING id, role_id, action, resource`,
+		role, p.Action, p.Resource)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrRoleNotFound
+	case isUniqueViolation(err):
+		return domain.ErrPermissionExists
+	}
+	return err
+}
+
+func (r *rbacRepository) DeletePermission(ctx context.Context, role string, id int64) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM rbac_permissions p USING rbac_roles r
+		WHERE p.role_id = r.id AND r.name = $1 AND p.id = $2`,
+		role, id)
+	return affected(res, err, domain.ErrPermissionNotFound)
+}
+
+func (r *rbacRepository) ListBindings(ctx context.Context, filter domain.RoleBindingFilter) ([]domain.RoleBinding, int64, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond, value string) {
+		if value != "" {
+			args = append(args, value)
+			conds = append(conds, cond+" = $"+strconv.Itoa(len(args)))
+		}
+	}
+	add("b.subject_kind", filter.SubjectKind)
+	add("b.subject", filter.Subject)
+	add("r.name", filter.Role)
+
+	from := " FROM rbac_role_bindings b JOIN rbac_roles r ON r.id = b.role_id"
+	if len(conds) > 0 {
+		from += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT count(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT b.id, b.subject_kind, b.subject, b.role_id, r.name AS role, b.created_at" + from + " ORDER BY b.id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+	var bindings []domain.RoleBinding
+	if err := r.db.SelectContext(ctx, &bindings, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return bindings, total, nil
+}
+
+func (r *rbacRepository) CreateBinding(ctx context.Context, b *domain.RoleBinding) error {
+	err := r.db.GetContext(ctx, b, `
+		INSERT INTO rbac_role_bindings (subject_kind, subject, role_id)
+		SELECT $1, $2, id FROM rbac_roles WHERE name = $3
+		RETURNING id, subject_kind, subject, role_id, $3::text AS role, created_at`,
+		b.SubjectKind, b.Subject, b.Role)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrRoleNotFound
+	case isUniqueViolation(err):
+		return domain.ErrRoleBindingExists
+	}
+	return err
+}
+
+func (r *rbacRepository) DeleteBinding(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM rbac_role_bindings WHERE id = $1", id)
+	return affected(res, err, domain.ErrRoleBindingNotFound)
+}
+
+func (r *rbacRepository) SubjectPermissions(ctx context.Context, kind, subject string, roles []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	err := r.db.SelectContext(ctx, &permissions, `
+		SELECT DISTINCT p.id, p.role_id, p.action, p.resource
+		FROM rbac_permissions p
+		JOIN rbac_roles r ON r.id = p.role_id
+		WHERE r.name = ANY($3)
+		   OR EXISTS (
+		       SELECT 1 FROM rbac_role_bindings b
+		       WHERE b.role_id = r.id AND b.subject_kind = $1 AND b.subject = $2
+		   )`,
+		kind, subject, pq.Array(roles))
+	return permissions, err
+}
+
+// affected возвращает notFound, если запрос не затронул ни одной строки
+func affected(res sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// nonNil - пустой срез вместо nil, чтобы в JSON был [], а не null
+func nonNil(permissions []domain.Permission) []domain.Permission {
+	if permissions == nil {
+		return []domain.Permission{}
+	}
+	return permissions
+}
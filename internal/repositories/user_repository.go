@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	userColumns = "id, name, email, created_at, updated_at"
+
+	userSelectByID = "SELECT " + userColumns + " FROM users WHERE id = $1"
+	userInsert     = "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING " + userColumns
+	userUpdate     = "UPDATE users SET name = $2, email = $3, updated_at = now() WHERE id = $1 RETURNING " + userColumns
+	userDelete     = "DELETE FROM users WHERE id = $1"
+)
+
+// userOrder - допустимые значения UserFilter.Sort и их ORDER BY; id - второй
+// ключ, чтобы страницы не пересекались при равных значениях
+var userOrder = map[string]string{
+	domain.UserSortID:              "id",
+	"-" + domain.UserSortID:        "id DESC",
+	domain.UserSortName:            "name, id",
+	"-" + domain.UserSortName:      "name DESC, id DESC",
+	domain.UserSortCreatedAt:       "created_at, id",
+	"-" + domain.UserSortCreatedAt: "created_at DESC, id DESC",
+}
+
+type userRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) interfaces.UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+func (r *userRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if filter.Query != "" {
+		add("(name ILIKE ? OR email ILIKE ?)", "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.Email != "" {
+		add("lower(email) = lower(?)", filter.Email)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at < ?", *filter.CreatedTo)
+	}
+	from := " FROM users"
+	if len(conds) > 0 {
+		from += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT count(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := userOrder[filter.Sort]
+	if !ok {
+		order = userOrder[domain.UserSortID]
+	}
+	query := "SELECT " + userColumns + from + " ORDER BY " + order
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+	var users []domain.User
+	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	var user domain.User
+	err := r.db.GetContext(ctx, &user, userSelectByID, id)
+	return userResult(&user, err)
+}
+
+func (r *userRepository) GetByIDForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error) {
+	var user domain.User
+	err := tx.Get(&user, userSelectByID+" FOR UPDATE", id)
+	return userResult(&user, err)
+}
+
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return userWriteError(r.db.GetContext(ctx, user, userInsert, user.Name, user.Email))
+}
+
+func (r *userRepository) CreateTx(tx *sqlx.Tx, user *domain.User) error {
+	return userWriteError(tx.Get(user, userInsert, user.Name, user.Email))
+}
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	return userWriteError(r.db.GetContext(ctx, user, userUpdate, user.ID, user.Name, user.Email))
+}
+
+func (r *userRepository) UpdateTx(tx *sqlx.Tx, user *domain.User) error {
+	return userWriteError(tx.Get(user, userUpdate, user.ID, user.Name, user.Email))
+}
+
+func (r *userRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, userDelete, id)
+	return affected(res, err, domain.ErrUserNotFound)
+}
+
+func (r *userRepository) DeleteTx(tx *sqlx.Tx, id int64) error {
+	res, err := tx.Exec(userDelete, id)
+	return affected(res, err, domain.ErrUserNotFound)
+}
+
+func userResult(user *domain.User, err error) (*domain.User, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// userWriteError переводит ошибки INSERT/UPDATE ... RETURNING в ошибки фичи
+func userWriteError(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrUserNotFound
+	case isUniqueViolation(err):
+		return domain.ErrUserEmailTaken
+	}
+	return err
+}
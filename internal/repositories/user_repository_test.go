@@ -0,0 +1,212 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/container"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var userRowColumns = []string{"id", "name", "email", "created_at", "updated_at"}
+
+func userRow(id int64, name string) []driver.Value {
+	now := time.Now()
+	return []driver.Value{id, name, strings.ToLower(name) + "@example.com", now, now}
+}
+
+// usersDB отвечает на count(*) числом total, на остальные SELECT и RETURNING -
+// строками rows, на DELETE - affected затронутыми строками
+func usersDB(total int64, affected int64, rows ...[]driver.Value) func(string) fakeResult {
+	return func(query string) fakeResult {
+		switch {
+		case strings.HasPrefix(query, "SELECT count(*)"):
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{total}}}
+		case strings.HasPrefix(query, "DELETE"):
+			return fakeResult{affected: affected}
+		}
+		return fakeResult{columns: userRowColumns, rows: rows}
+	}
+}
+
+// newUserRepository - репозиторий модуля users из контейнера поверх fakeDB
+func newUserRepository(t *testing.T, db *sqlx.DB) interfaces.UserRepository {
+	return container.MustResolve[interfaces.UserRepository](t,
+		container.WithOverride(func() *sqlx.DB { return db }),
+	)
+}
+
+func TestUserRepository_ListFilters(t *testing.T) {
+	db, fake := newFakeDB(usersDB(7, 0, userRow(1, "Anna"), userRow(2, "Boris")))
+	repo := newUserRepository(t, db)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	users, total, err := repo.List(context.Background(), domain.UserFilter{
+		Query:       `50%_off\`,
+		Email:       "Anna@Example.com",
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Sort:        "-created_at",
+		Limit:       10,
+		Offset:      20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 7 || len(users) != 2 || users[1].Name != "Boris" {
+		t.Fatalf("total = %d, users = %+v", total, users)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 2 {
+		t.Fatalf("queries = %d, want count and select", len(queries))
+	}
+	where := " FROM users WHERE (name ILIKE $1 OR email ILIKE $1) AND lower(email) = lower($2)" +
+		" AND created_at >= $3 AND created_at < $4"
+	if want := "SELECT count(*)" + where; queries[0].query != want {
+		t.Fatalf("count query:\n%s\nwant:\n%s", queries[0].query, want)
+	}
+	if !strings.HasSuffix(queries[1].query, where+" ORDER BY created_at DESC, id DESC LIMIT $5 OFFSET $6") {
+		t.Fatalf("select query: %s", queries[1].query)
+	}
+
+	// % и _ из запроса пользователя - литералы, а не шаблоны ILIKE
+	args := queries[1].args
+	if args[0] != `%50\%\_off\\%` || args[1] != "Anna@Example.com" || args[4] != 10 || args[5] != 20 {
+		t.Fatalf("select args = %v", args)
+	}
+	if len(queries[0].args) != 4 {
+		t.Fatalf("count args = %v, want filters without limit", queries[0].args)
+	}
+}
+
+func TestUserRepository_ListWithoutFilters(t *testing.T) {
+	db, fake := newFakeDB(usersDB(0, 0))
+	repo := newUserRepository(t, db)
+
+	users, total, err := repo.List(context.Background(), domain.UserFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 || len(users) != 0 {
+		t.Fatalf("total = %d, users = %v", total, users)
+	}
+	queries := fake.Queries()
+	if queries[0].query != "SELECT count(*) FROM users" {
+		t.Fatalf("count query: %s", queries[0].query)
+	}
+	if !strings.HasSuffix(queries[1].query, " FROM users ORDER BY id") || len(queries[1].args) != 0 {
+		t.Fatalf("select query without limit: %s %v", queries[1].query, queries[1].args)
+	}
+}
+
+func TestUserRepository_SortWhitelist(t *testing.T) {
+	for sort, order := range map[string]string{
+		"":                          "id",
+		"id":                        "id",
+		"-id":                       "id DESC",
+		"name":                      "name, id",
+		"-name":                     "name DESC, id DESC",
+		"created_at":                "created_at, id",
+		"email":                     "id",
+		"name; DROP TABLE users --": "id",
+	} {
+		t.Run(sort, func(t *testing.T) {
+			db, fake := newFakeDB(usersDB(0, 0))
+			repo := newUserRepository(t, db)
+			if _, _, err := repo.List(context.Background(), domain.UserFilter{Sort: sort}); err != nil {
+				t.Fatal(err)
+			}
+			if query := fake.Queries()[1].query; !strings.HasSuffix(query, " ORDER BY "+order) {
+				t.Fatalf("sort %q: %s, want ORDER BY %s", sort, query, order)
+			}
+		})
+	}
+}
+
+func TestUserRepository_TxVariants(t *testing.T) {
+	db, fake := newFakeDB(usersDB(0, 0, userRow(42, "Anna")))
+	repo := newUserRepository(t, db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := repo.GetByIDForUpdateTx(tx, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Name = "Anna Petrova"
+	if err := repo.UpdateTx(tx, user); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateTx(tx, &domain.User{Name: "Boris", Email: "boris@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	// DELETE не затронул строк - пользователя нет
+	if err := repo.DeleteTx(tx, 7); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("DeleteTx = %v, want user_not_found", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 4 {
+		t.Fatalf("queries = %d, want 4", len(queries))
+	}
+	if want := "SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1 FOR UPDATE"; queries[0].query != want {
+		t.Fatalf("lock query: %s", queries[0].query)
+	}
+	if !strings.HasPrefix(queries[1].query, "UPDATE users SET name = $2, email = $3") || queries[1].args[1] != "Anna Petrova" {
+		t.Fatalf("update query: %s %v", queries[1].query, queries[1].args)
+	}
+	for _, q := range queries {
+		if !q.inTx {
+			t.Fatalf("query outside of transaction: %s", q.query)
+		}
+	}
+	if events := fake.Events(); strings.Join(events, ",") != "BEGIN,ROLLBACK" {
+		t.Fatalf("events = %v", events)
+	}
+}
+
+func TestUserRepository_WriteErrors(t *testing.T) {
+	unique := &pq.Error{Code: "23505", Constraint: "users_email_key"}
+	db, _ := newFakeDB(func(query string) fakeResult {
+		if strings.HasPrefix(query, "INSERT") {
+			return fakeResult{err: unique}
+		}
+		// UPDATE ... RETURNING без строк - пользователя нет
+		return fakeResult{columns: userRowColumns}
+	})
+	repo := newUserRepository(t, db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	user := &domain.User{ID: 42, Name: "Anna", Email: "anna@example.com"}
+	if err := repo.Create(context.Background(), user); !errors.Is(err, domain.ErrUserEmailTaken) {
+		t.Fatalf("Create = %v, want user_email_taken", err)
+	}
+	if err := repo.CreateTx(tx, user); !errors.Is(err, domain.ErrUserEmailTaken) {
+		t.Fatalf("CreateTx = %v, want user_email_taken", err)
+	}
+	if err := repo.Update(context.Background(), user); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("Update = %v, want user_not_found", err)
+	}
+	if err := repo.UpdateTx(tx, user); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("UpdateTx = %v, want user_not_found", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package reqctx - значения HTTP запроса в контексте: идентификатор запроса,
+// адрес клиента и аутентифицированный субъект. Их кладут middleware, читают журналы,
+// рендер ошибок, rate limiting и код, передающий контекст дальше (use cases, consumers).
+package reqctx
+
+import "context"
+
+type requestIDKey struct{}
+
+type clientIPKey struct{}
+
+// WithRequestID возвращает контекст с идентификатором запроса
+// (например, для передачи в consumer вместе с сообщением)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса или пустую строку
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithClientIP возвращает контекст с адресом клиента
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP возвращает адрес клиента, определённый middleware RealIP, или пустую строку
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+type subjectKey struct{}
+
+// WithSubject возвращает контекст с идентификатором аутентифицированного
+// субъекта (пользователя или API ключа)
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Subject возвращает идентификатор аутентифицированного субъекта или пустую строку
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
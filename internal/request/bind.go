@@ -0,0 +1,181 @@
+// Package request - разбор и валидация HTTP запросов в контроллерах.
+// Bind[T] собирает структуру из тела (JSON или форма), query и path параметров,
+// проверяет теги validate и возвращает *apperrors.Error, готовый для apperrors.Write.
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+// DefaultMaxBodyBytes - лимит тела запроса в Bind. Лимит middleware body_limit
+// действует раньше и может быть только меньше.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Validatable - запрос с собственными проверками (например, зависимость полей друг от друга).
+// Validate вызывается после проверки тегов и должен возвращать *apperrors.Error.
+type Validatable interface {
+	Validate() error
+}
+
+// Bind разбирает запрос в T и валидирует его:
+//   - тело по Content-Type: application/json (поля по тегу json) или форма
+//     (application/x-www-form-urlencoded, multipart/form-data; поля по тегу form).
+//     Неизвестные поля тела - ошибка валидации, тело больше DefaultMaxBodyBytes - 413;
+//   - query параметры - поля с тегом query, лишние параметры игнорируются;
+//   - параметры пути chi - поля с тегом path;
+//   - значение тега default для отсутствующих query, path и form параметров;
+//   - теги validate (см. Validate) и метод Validate, если T реализует Validatable.
+//     Правила, кроме required, не проверяются только у полей, которых нет в запросе:
+//     присланный ?page=0 нарушает min=1.
+//
+// Ошибки - *apperrors.Error: 400 для некорректного тела, 415 для неизвестного
+// Content-Type, 422 с ошибками по полям для нарушений правил.
+func Bind[T any](r *http.Request) (T, error) {
+	var v T
+	if err := checkType(reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return v, apperrors.Internal(err)
+	}
+	present := presence{}
+	if err := decodeBody(r, &v, present); err != nil {
+		return v, err
+	}
+	if err := decodeValues(r, &v, present); err != nil {
+		return v, err
+	}
+	if err := validate(&v, present); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// decodeBody разбирает тело запроса и отмечает присланные поля в present;
+// пустое тело оставляет поля нулевыми, обязательность проверяют теги validate
+func decodeBody(r *http.Request, dst interface{}, present presence) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	if r.ContentLength > DefaultMaxBodyBytes {
+		return apperrors.New(apperrors.KindPayloadTooLarge, "request body too large")
+	}
+
+	mediaType := "application/json"
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+			return apperrors.New(apperrors.KindUnsupportedMediaType, "invalid Content-Type %q", ct)
+		}
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return decodeJSON(r, dst, present)
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		return decodeForm(r, dst, present)
+	default:
+		return apperrors.New(apperrors.KindUnsupportedMediaType, "unsupported Content-Type %q", mediaType)
+	}
+}
+
+func decodeJSON(r *http.Request, dst interface{}, present presence) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, DefaultMaxBodyBytes+1))
+	if err != nil {
+		return apperrors.As(err)
+	}
+	if len(body) > DefaultMaxBodyBytes {
+		return apperrors.New(apperrors.KindPayloadTooLarge, "request body too large")
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return jsonError(err)
+	}
+	if dec.More() {
+		return apperrors.BadRequest("request body must contain a single JSON value").WithCode("malformed_body")
+	}
+
+	// Тело уже разобрано в dst, повторный разбор только собирает имена присланных полей
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return apperrors.As(err)
+	}
+	present.addJSON(doc, "")
+	return nil
+}
+
+// addJSON отмечает ключи объектов и индексы массивов документа JSON (items.0.name)
+func (p presence) addJSON(doc interface{}, prefix string) {
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			p.add(prefix + key)
+			p.addJSON(value, prefix+key+".")
+		}
+	case []interface{}:
+		for i, value := range node {
+			name := prefix + strconv.Itoa(i)
+			p.add(name)
+			p.addJSON(value, name+".")
+		}
+	}
+}
+
+// jsonError переводит ошибку encoding/json в ошибку для клиента
+func jsonError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return apperrors.BadRequest("malformed JSON at offset %d", syntaxErr.Offset).WithCode("malformed_body")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return apperrors.BadRequest("malformed JSON: unexpected end of body").WithCode("malformed_body")
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			return apperrors.BadRequest("request body must be a JSON %s", typeErr.Type.Kind()).WithCode("malformed_body")
+		}
+		return apperrors.Validation(apperrors.FieldError{
+			Field:   field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be %s", typeName(typeErr.Type)),
+		})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json не экспортирует тип этой ошибки
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return apperrors.Validation(apperrors.FieldError{Field: field, Code: "unknown", Message: "unknown field"})
+	}
+	return apperrors.As(err)
+}
+
+// typeName - имя типа для сообщений клиенту без имён Go пакетов
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,456 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidRe   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Validate проверяет поля структуры v по тегам validate и, если v реализует
+// Validatable, вызывает его Validate. Правила перечисляются через запятую:
+//
+//	required      - значение не нулевое (указатель не nil, срез и строка не пустые)
+//	min=N, max=N  - для строк длина в символах, для срезов и map - число элементов,
+//	                для чисел - значение
+//	len=N         - точная длина строки, среза или map
+//	oneof=a b c   - значение из списка
+//	email, url, uuid - формат строки
+//
+// Validate не знает, какие поля прислал клиент, поэтому нулевое необязательное поле
+// считает отсутствующим и остальными правилами не проверяет; Bind проверяет
+// присланные нулевые значения (min=1 отклоняет 0). Вложенные структуры
+// и срезы структур проверяются рекурсивно, имя поля в ошибке - через точку (items.0.name).
+// Все нарушения возвращаются одной ошибкой apperrors.Validation.
+// Теги разбираются один раз для типа; неизвестное правило или правило,
+// неприменимое к типу поля, - ошибка apperrors.Internal (см. CheckRules).
+func Validate(v interface{}) error {
+	return validate(v, nil)
+}
+
+// presence - имена присланных полей запроса в формате ошибок валидации (items.0.name).
+// Имена хранятся в нижнем регистре: encoding/json сопоставляет ключи без учёта регистра.
+type presence map[string]bool
+
+func (p presence) add(name string) {
+	p[strings.ToLower(name)] = true
+}
+
+func (p presence) has(name string) bool {
+	return p[strings.ToLower(name)]
+}
+
+// validate - Validate с известным набором присланных полей; present == nil - набор неизвестен
+func validate(v interface{}, present presence) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		fields, err := validateStruct(rv, "", present)
+		if err != nil {
+			return apperrors.Internal(err)
+		}
+		if len(fields) > 0 {
+			return apperrors.Validation(fields...)
+		}
+	}
+
+	if vv, ok := v.(Validatable); ok {
+		if err := vv.Validate(); err != nil {
+			var e *apperrors.Error
+			if errors.As(err, &e) {
+				return err
+			}
+			return apperrors.New(apperrors.KindValidation, "%s", err.Error())
+		}
+	}
+	return nil
+}
+
+// CheckRules разбирает теги validate, default и типы параметров запроса T
+// и вложенных в него структур. Bind делает то же при первом запросе с типом T
+// и отвечает 500 на ошибку в тегах; CheckRules позволяет найти её тестом.
+func CheckRules[T any]() error {
+	return checkType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// checkedTypes - reflect.Type -> error проверки тегов (nil - теги корректны)
+var checkedTypes sync.Map
+
+// checkType - checkRules с кешем результата для типа
+func checkType(t reflect.Type) error {
+	if err, ok := checkedTypes.Load(t); ok {
+		e, _ := err.(error)
+		return e
+	}
+	err := checkRules(t, make(map[reflect.Type]bool))
+	checkedTypes.Store(t, err)
+	return err
+}
+
+func checkRules(t reflect.Type, seen map[reflect.Type]bool) error {
+	t = indirect(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return checkRules(t.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if t == timeType || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	fields, err := structRules(t)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if err := checkRules(t.Field(f.index).Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rule - разобранное правило тега validate
+type rule struct {
+	key   string
+	param string
+	limit float64
+}
+
+// fieldRules - правила поля структуры
+type fieldRules struct {
+	index int
+	name  string
+	// embedded - встроенная структура без имени в json, её поля проверяются без префикса
+	embedded bool
+	required bool
+	rules    []rule
+}
+
+// compiledRules - результат разбора тегов типа, кешируется в rulesCache
+type compiledRules struct {
+	fields []fieldRules
+	err    error
+}
+
+// rulesCache - reflect.Type -> *compiledRules
+var rulesCache sync.Map
+
+// structRules возвращает правила полей структуры t, разбирая теги при первом обращении
+func structRules(t reflect.Type) ([]fieldRules, error) {
+	if c, ok := rulesCache.Load(t); ok {
+		compiled := c.(*compiledRules)
+		return compiled.fields, compiled.err
+	}
+	fields, err := compileRules(t)
+	rulesCache.Store(t, &compiledRules{fields: fields, err: err})
+	return fields, err
+}
+
+func compileRules(t reflect.Type) ([]fieldRules, error) {
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && tagName(sf.Tag.Get("json")) == "" {
+			fields = append(fields, fieldRules{index: i, embedded: true})
+			continue
+		}
+		for _, tag := range []string{"query", "path", "form"} {
+			if sf.Anonymous || tagName(sf.Tag.Get(tag)) == "" {
+				continue
+			}
+			if err := checkParam(sf, tag); err != nil {
+				return nil, fmt.Errorf("request: %s.%s: %w", t, sf.Name, err)
+			}
+		}
+		name := fieldName(sf)
+		if name == "" {
+			continue
+		}
+
+		f := fieldRules{index: i, name: name}
+		tag := sf.Tag.Get("validate")
+		if tag == "" {
+			fields = append(fields, f)
+			continue
+		}
+		for _, text := range strings.Split(tag, ",") {
+			r, err := compileRule(indirect(sf.Type), text)
+			if err != nil {
+				return nil, fmt.Errorf("request: %s.%s: %w", t, sf.Name, err)
+			}
+			if r.key == "required" {
+				f.required = true
+				continue
+			}
+			f.rules = append(f.rules, r)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// compileRule разбирает правило и проверяет, что оно применимо к типу поля t.
+// Тип значения поля-интерфейса проверяется при валидации.
+func compileRule(t reflect.Type, text string) (rule, error) {
+	key, param, _ := strings.Cut(text, "=")
+	r := rule{key: key, param: param}
+	switch key {
+	case "required":
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return r, fmt.Errorf("rule %s needs a number, got %q", key, param)
+		}
+		r.limit = limit
+		if _, ok := measureKind(t.Kind()); !ok && t.Kind() != reflect.Interface {
+			return r, fmt.Errorf("rule %s is not supported for %s", key, t)
+		}
+	case "oneof":
+		if len(strings.Fields(param)) == 0 {
+			return r, fmt.Errorf("rule oneof needs a list of values")
+		}
+	case "email", "url", "uuid":
+		if t.Kind() != reflect.String && t.Kind() != reflect.Interface {
+			return r, fmt.Errorf("rule %s is not supported for %s", key, t)
+		}
+	default:
+		return r, fmt.Errorf("unknown validation rule %q", key)
+	}
+	return r, nil
+}
+
+func validateStruct(v reflect.Value, prefix string, present presence) ([]apperrors.FieldError, error) {
+	rules, err := structRules(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []apperrors.FieldError
+	for _, f := range rules {
+		var fieldErrs []apperrors.FieldError
+		if f.embedded {
+			fieldErrs, err = validateStruct(v.Field(f.index), prefix, present)
+		} else {
+			fieldErrs, err = validateField(v.Field(f.index), prefix+f.name, f, present)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fieldErrs...)
+	}
+	return fields, nil
+}
+
+func validateField(v reflect.Value, name string, f fieldRules, present presence) ([]apperrors.FieldError, error) {
+	if f.required && isEmpty(v) {
+		return []apperrors.FieldError{{Field: name, Code: "required", Message: "is required"}}, nil
+	}
+	// Отсутствующее необязательное поле остальными правилами не проверяется
+	if !isPresent(v, name, present) {
+		return nil, nil
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	var fields []apperrors.FieldError
+	for _, r := range f.rules {
+		msg, err := checkRule(v, r)
+		if err != nil {
+			return nil, fmt.Errorf("request: field %s: %w", name, err)
+		}
+		if msg != "" {
+			fields = append(fields, apperrors.FieldError{Field: name, Code: r.key, Message: msg})
+		}
+	}
+	if len(fields) > 0 {
+		return fields, nil
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != timeType:
+		return validateStruct(v, name+".", present)
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			for item.Kind() == reflect.Ptr && !item.IsNil() {
+				item = item.Elem()
+			}
+			if item.Kind() == reflect.Struct && item.Type() != timeType {
+				itemFields, err := validateStruct(item, name+"."+strconv.Itoa(i)+".", present)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, itemFields...)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// isPresent сообщает, прислал ли клиент поле. null в JSON для указателя - поле отсутствует.
+// Без набора присланных полей отсутствующим считается нулевое значение.
+func isPresent(v reflect.Value, name string, present presence) bool {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false
+	}
+	if present == nil {
+		return !isEmpty(v)
+	}
+	return present.has(name)
+}
+
+// checkRule возвращает сообщение о нарушении правила или пустую строку.
+// Ошибка - правило неприменимо к значению поля-интерфейса.
+func checkRule(v reflect.Value, r rule) (string, error) {
+	switch r.key {
+	case "min", "max", "len":
+		size, unit, ok := measure(v)
+		if !ok {
+			return "", fmt.Errorf("rule %s is not supported for %s", r.key, v.Type())
+		}
+		switch {
+		case r.key == "min" && size < r.limit:
+			return fmt.Sprintf("must be at least %s%s", r.param, unit), nil
+		case r.key == "max" && size > r.limit:
+			return fmt.Sprintf("must be at most %s%s", r.param, unit), nil
+		case r.key == "len" && size != r.limit:
+			return fmt.Sprintf("must be exactly %s%s", r.param, unit), nil
+		}
+	case "oneof":
+		value := fmt.Sprint(v.Interface())
+		options := strings.Fields(r.param)
+		for _, option := range options {
+			if value == option {
+				return "", nil
+			}
+		}
+		return "must be one of: " + strings.Join(options, ", "), nil
+	case "email", "url", "uuid":
+		if v.Kind() != reflect.String {
+			return "", fmt.Errorf("rule %s is not supported for %s", r.key, v.Type())
+		}
+		return checkFormat(v.String(), r.key), nil
+	}
+	return "", nil
+}
+
+// checkFormat проверяет формат строки для правил email, url и uuid
+func checkFormat(value, key string) string {
+	switch key {
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return "must be a valid email address"
+		}
+	case "url":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL"
+		}
+	case "uuid":
+		if !uuidRe.MatchString(value) {
+			return "must be a UUID"
+		}
+	}
+	return ""
+}
+
+// measure возвращает величину для min/max/len и единицу измерения для сообщения
+func measure(v reflect.Value) (float64, string, bool) {
+	unit, ok := measureKind(v.Kind())
+	if !ok {
+		return 0, "", false
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), unit, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), unit, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), unit, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), unit, true
+	default:
+		return v.Float(), unit, true
+	}
+}
+
+// measureKind возвращает единицу измерения min/max/len для вида значения
+// и false, если эти правила к нему неприменимы
+func measureKind(kind reflect.Kind) (string, bool) {
+	switch kind {
+	case reflect.String:
+		return " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "", true
+	}
+	return "", false
+}
+
+// indirect возвращает тип значения за указателями
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// fieldName - имя поля в запросе: из тега json, form, query или path, иначе имя поля Go.
+// Поле json:"-" с тегом query или path называется по нему; поле, скрытое
+// во всех своих тегах, не проверяется.
+func fieldName(sf reflect.StructField) string {
+	tagged := false
+	for _, tag := range []string{"json", "form", "query", "path"} {
+		if value, ok := sf.Tag.Lookup(tag); ok {
+			tagged = true
+			if name := tagName(value); name != "" {
+				return name
+			}
+		}
+	}
+	if tagged {
+		return ""
+	}
+	return sf.Name
+}
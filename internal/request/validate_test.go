@@ -0,0 +1,134 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+)
+
+type listRequest struct {
+	Query   string `json:"-" query:"q" validate:"max=5"`
+	Email   string `json:"-" query:"email" validate:"email"`
+	Page    int    `json:"-" query:"page" default:"1" validate:"min=1"`
+	PerPage int    `json:"-" query:"per_page" default:"20" validate:"min=1,max=100"`
+}
+
+type updateRequest struct {
+	Name  *string `json:"name" validate:"min=1,max=10"`
+	Count int     `json:"count" validate:"min=1"`
+	Items []struct {
+		Title string `json:"title" validate:"min=1"`
+	} `json:"items"`
+}
+
+// fieldCodes возвращает поле -> код нарушения из ошибки валидации
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var e *apperrors.Error
+	if !errors.As(err, &e) || e.Kind != apperrors.KindValidation {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	codes := make(map[string]string)
+	for _, f := range e.Fields {
+		codes[f.Field] = f.Code
+	}
+	return codes
+}
+
+func TestBindQueryPresence(t *testing.T) {
+	// Отсутствующие параметры получают default и правилами не проверяются
+	req, err := Bind[listRequest](httptest.NewRequest(http.MethodGet, "/users", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Page != 1 || req.PerPage != 20 {
+		t.Fatalf("defaults: page = %d, per_page = %d", req.Page, req.PerPage)
+	}
+
+	// Присланный ноль и пустой email проверяются
+	_, err = Bind[listRequest](httptest.NewRequest(http.MethodGet, "/users?page=0&per_page=0&email=", nil))
+	codes := fieldCodes(t, err)
+	if codes["page"] != "min" || codes["per_page"] != "min" || codes["email"] != "email" {
+		t.Fatalf("codes = %v", codes)
+	}
+	if _, ok := codes["q"]; ok {
+		t.Fatalf("absent q must not be validated: %v", codes)
+	}
+}
+
+func TestBindJSONPresence(t *testing.T) {
+	bind := func(body string) error {
+		r := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		_, err := Bind[updateRequest](r)
+		return err
+	}
+
+	if err := bind(`{"name": null, "items": []}`); err != nil {
+		t.Fatalf("absent fields and null pointer: %v", err)
+	}
+
+	codes := fieldCodes(t, bind(`{"name": "", "count": 0, "items": [{"title": ""}]}`))
+	if codes["name"] != "min" || codes["count"] != "min" || codes["items.0.title"] != "min" {
+		t.Fatalf("codes = %v", codes)
+	}
+}
+
+func TestValidateWithoutPresence(t *testing.T) {
+	// Без Bind нулевое значение считается отсутствующим
+	if err := Validate(&listRequest{}); err != nil {
+		t.Fatalf("zero values: %v", err)
+	}
+	codes := fieldCodes(t, Validate(&listRequest{Query: "too long", PerPage: 500}))
+	if codes["q"] != "max" || codes["per_page"] != "max" {
+		t.Fatalf("codes = %v", codes)
+	}
+}
+
+type badRuleRequest struct {
+	Name string `json:"name" validate:"requried"`
+}
+
+type badLimitRequest struct {
+	Active bool `json:"active" validate:"min=1"`
+}
+
+type badDefaultRequest struct {
+	Page int `json:"-" query:"page" default:"first"`
+}
+
+type nestedBadRequest struct {
+	Items []badRuleRequest `json:"items"`
+}
+
+func TestCheckRules(t *testing.T) {
+	if err := CheckRules[listRequest](); err != nil {
+		t.Fatalf("valid tags: %v", err)
+	}
+	for name, check := range map[string]func() error{
+		"unknown rule":      CheckRules[badRuleRequest],
+		"min on bool":       CheckRules[badLimitRequest],
+		"invalid default":   CheckRules[badDefaultRequest],
+		"nested struct tag": CheckRules[nestedBadRequest],
+	} {
+		if err := check(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBadTagsReturnInternalError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "x"}`))
+	r.Header.Set("Content-Type", "application/json")
+	_, err := Bind[badRuleRequest](r)
+	if e := apperrors.As(err); e.Kind != apperrors.KindInternal {
+		t.Fatalf("Bind: expected an internal error, got %v", err)
+	}
+	if e := apperrors.As(Validate(&badLimitRequest{Active: true})); e.Kind != apperrors.KindInternal {
+		t.Fatalf("Validate: expected an internal error, got %v", e)
+	}
+}
@@ -0,0 +1,251 @@
+package request
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// lookupFunc возвращает значения параметра name и признак его наличия
+type lookupFunc func(name string) ([]string, bool)
+
+// decodeValues заполняет поля с тегами query и path и отмечает присланные в present
+func decodeValues(r *http.Request, dst interface{}, present presence) error {
+	v := reflect.ValueOf(dst).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	query := r.URL.Query()
+	fields := setFields(v, "query", func(name string) ([]string, bool) {
+		values, ok := query[name]
+		return values, ok
+	}, present, nil)
+	fields = append(fields, setFields(v, "path", func(name string) ([]string, bool) {
+		if value := chi.URLParam(r, name); value != "" {
+			return []string{value}, true
+		}
+		return nil, false
+	}, present, nil)...)
+
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
+
+// decodeForm заполняет поля с тегом form из тела формы. Файлы multipart формы
+// не разбираются - их читает контроллер через r.FormFile.
+func decodeForm(r *http.Request, dst interface{}, present presence) error {
+	// ResponseWriter не нужен: MaxBytesReader использует его только чтобы закрыть соединение
+	r.Body = http.MaxBytesReader(nil, r.Body, DefaultMaxBodyBytes)
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		err = r.ParseMultipartForm(DefaultMaxBodyBytes)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		if e := apperrors.As(err); e.Kind == apperrors.KindPayloadTooLarge {
+			return e
+		}
+		return apperrors.BadRequest("malformed form body").WithCode("malformed_body").Wrap(err)
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	fields := setFields(v, "form", func(name string) ([]string, bool) {
+		values, ok := r.PostForm[name]
+		return values, ok
+	}, present, known)
+
+	var unknown []string
+	for key := range r.PostForm {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		fields = append(fields, apperrors.FieldError{Field: key, Code: "unknown", Message: "unknown field"})
+	}
+
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
+
+// setFields заполняет поля структуры v с тегом tag значениями из lookup
+// (встроенные структуры - рекурсивно) и возвращает ошибки преобразования.
+// Присланные параметры отмечаются в present, отсутствующие получают значение
+// тега default. В known, если он задан, отмечаются имена всех полей с тегом.
+func setFields(v reflect.Value, tag string, lookup lookupFunc, present presence, known map[string]bool) []apperrors.FieldError {
+	var fields []apperrors.FieldError
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, setFields(v.Field(i), tag, lookup, present, known)...)
+			continue
+		}
+		name := tagName(sf.Tag.Get(tag))
+		if name == "" || !sf.IsExported() {
+			continue
+		}
+		if known != nil {
+			known[name] = true
+		}
+		raw, ok := lookup(name)
+		if !ok || len(raw) == 0 {
+			def, ok := sf.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+			// Тег проверен при разборе типа (CheckRules), ошибки здесь нет
+			if err := setValue(v.Field(i), []string{def}); err != nil {
+				fields = append(fields, apperrors.FieldError{Field: name, Code: "type", Message: err.Error()})
+			}
+			continue
+		}
+		present.add(name)
+		if err := setValue(v.Field(i), raw); err != nil {
+			fields = append(fields, apperrors.FieldError{Field: name, Code: "type", Message: err.Error()})
+		}
+	}
+	return fields
+}
+
+// setValue преобразует строковые значения параметра в значение поля.
+// Срез заполняется повторяющимися параметрами (?tag=a&tag=b), остальные типы - первым значением.
+func setValue(v reflect.Value, raw []string) error {
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType) {
+		s := reflect.MakeSlice(v.Type(), len(raw), len(raw))
+		for i, item := range raw {
+			if err := setValue(s.Index(i), []string{item}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setScalar(v, raw[0])
+}
+
+func setScalar(v reflect.Value, raw string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid value")
+		}
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("must be a duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	invalid := fmt.Errorf("must be %s", typeName(v.Type()))
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported parameter type %s", v.Type())
+	}
+	return nil
+}
+
+// paramSupported сообщает, может ли setValue заполнить поле типа t из параметра запроса
+func paramSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return paramSupported(t.Elem())
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return paramSupported(t.Elem())
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// checkParam проверяет поле с тегом query, path или form: тип заполняется
+// из параметра, значение тега default преобразуется в этот тип
+func checkParam(sf reflect.StructField, tag string) error {
+	if !paramSupported(sf.Type) {
+		return fmt.Errorf("%s parameter of type %s is not supported", tag, sf.Type)
+	}
+	if def, ok := sf.Tag.Lookup("default"); ok {
+		if err := setValue(reflect.New(sf.Type).Elem(), []string{def}); err != nil {
+			return fmt.Errorf("default %q: %w", def, err)
+		}
+	}
+	return nil
+}
+
+// tagName возвращает имя из тега вида "name,omitempty"; "-" - поле пропускается
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,212 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Format - формат тела ответа
+type Format struct {
+	ContentType string
+	Encode      func(w io.Writer, v interface{}, opts Options) error
+}
+
+// JSONFormat - формат по умолчанию, отступы - при Options.Pretty
+var JSONFormat = Format{
+	ContentType: "application/json",
+	Encode: func(w io.Writer, v interface{}, opts Options) error {
+		enc := json.NewEncoder(w)
+		if opts.Pretty {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(v)
+	},
+}
+
+// MessagePack - бинарный формат; имена полей берутся из тегов json,
+// поэтому структуры ответа не нужно размечать отдельно
+var MessagePack = Format{
+	ContentType: "application/msgpack",
+	Encode: func(w io.Writer, v interface{}, _ Options) error {
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json")
+		return enc.Encode(v)
+	},
+}
+
+// CSV - таблица из среза структур: заголовок из тегов csv (иначе json),
+// вложенные структуры и срезы не поддерживаются. Paginated отдаёт в CSV только элементы.
+var CSV = Format{
+	ContentType: "text/csv; charset=utf-8",
+	Encode: func(w io.Writer, v interface{}, _ Options) error {
+		return encodeCSV(w, v)
+	},
+}
+
+// negotiate выбирает формат по заголовку Accept: JSON и formats в порядке
+// предпочтения обработчика, при равном q побеждает более ранний
+func negotiate(r *http.Request, formats []Format) (Format, bool) {
+	accept := r.Header.Get("Accept")
+	if len(formats) == 0 || accept == "" {
+		return JSONFormat, true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := Format{}, 0.0
+	for _, f := range append([]Format{JSONFormat}, formats...) {
+		if q := quality(ranges, f.ContentType); q > bestQ {
+			best, bestQ = f, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// mediaRange - элемент заголовка Accept
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality возвращает q самого точного диапазона Accept, подходящего под contentType
+func quality(ranges []mediaRange, contentType string) float64 {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, mr := range ranges {
+		s := -1
+		switch mr.mediaType {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = mr.q, s
+		}
+	}
+	return q
+}
+
+// offered - список форматов для сообщения 406
+func offered(formats []Format) string {
+	types := []string{JSONFormat.ContentType}
+	for _, f := range formats {
+		mediaType, _, _ := mime.ParseMediaType(f.ContentType)
+		types = append(types, mediaType)
+	}
+	return strings.Join(types, ", ")
+}
+
+func encodeCSV(w io.Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("csv: expected a slice of structs, got %T", v)
+	}
+	elem := rv.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: expected a slice of structs, got %T", v)
+	}
+
+	var columns []int
+	var header []string
+	for i := 0; i < elem.NumField(); i++ {
+		sf := elem.Field(i)
+		if name := csvName(sf); name != "" {
+			columns = append(columns, i)
+			header = append(header, name)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		for item.Kind() == reflect.Ptr {
+			item = item.Elem()
+		}
+		for j, field := range columns {
+			if item.IsValid() {
+				record[j] = csvValue(item.Field(field))
+			} else {
+				record[j] = ""
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvName - имя столбца из тега csv или json; "-" и неэкспортируемые поля пропускаются
+func csvName(sf reflect.StructField) string {
+	if !sf.IsExported() {
+		return ""
+	}
+	for _, tag := range []string{"csv", "json"} {
+		if value, ok := sf.Tag.Lookup(tag); ok {
+			name, _, _ := strings.Cut(value, ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
+
+func csvValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v.Interface())
+}
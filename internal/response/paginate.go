@@ -0,0 +1,93 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Параметры query, из которых строятся ссылки Link на соседние страницы
+const (
+	PageParam    = "page"
+	PerPageParam = "per_page"
+)
+
+// Page - страница выборки: номер с 1, размер и общее число элементов
+type Page struct {
+	Page    int
+	PerPage int
+	Total   int64
+}
+
+// Pagination - сведения о странице в теле ответа
+type Pagination struct {
+	Page       int   `json:"page" example:"2"`
+	PerPage    int   `json:"per_page" example:"20"`
+	Total      int64 `json:"total" example:"135"`
+	TotalPages int   `json:"total_pages" example:"7"`
+}
+
+// PageResponse - тело ответа Paginated
+type PageResponse[T any] struct {
+	Items      []T        `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Paginated отвечает 200 со страницей items. Общее число элементов - также
+// в заголовке X-Total-Count, ссылки на первую, предыдущую, следующую и последнюю
+// страницы - в заголовке Link (RFC 8288). В CSV отдаются только элементы.
+func Paginated[T any](w http.ResponseWriter, r *http.Request, items []T, page Page, formats ...Format) {
+	if items == nil {
+		items = []T{}
+	}
+	pagination := Pagination{
+		Page:    page.Page,
+		PerPage: page.PerPage,
+		Total:   page.Total,
+	}
+	if page.PerPage > 0 {
+		pagination.TotalPages = int((page.Total + int64(page.PerPage) - 1) / int64(page.PerPage))
+	}
+
+	format, ok := negotiate(r, formats)
+	if !ok {
+		notAcceptable(w, r, formats)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	if link := linkHeader(r, pagination); link != "" {
+		w.Header().Set("Link", link)
+	}
+
+	if format.ContentType == CSV.ContentType {
+		respondAs(w, r, http.StatusOK, items, format, formats)
+		return
+	}
+	respondAs(w, r, http.StatusOK, PageResponse[T]{Items: items, Pagination: pagination}, format, formats)
+}
+
+func linkHeader(r *http.Request, p Pagination) string {
+	if p.PerPage <= 0 || p.TotalPages == 0 {
+		return ""
+	}
+	var links []string
+	add := func(page int, rel string) {
+		u := *r.URL
+		q := u.Query()
+		q.Set(PageParam, strconv.Itoa(page))
+		q.Set(PerPageParam, strconv.Itoa(p.PerPage))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel))
+	}
+	add(1, "first")
+	if p.Page > 1 {
+		add(p.Page-1, "prev")
+	}
+	if p.Page < p.TotalPages {
+		add(p.Page+1, "next")
+	}
+	add(p.TotalPages, "last")
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,125 @@
+// Package response - ответы контроллеров: JSON, Created с Location, NoContent
+// и Paginated. Тело кодируется в буфер до записи заголовков, поэтому ошибка
+// кодирования превращается в 500 и попадает в журнал, а не обрывает ответ.
+// Обработчик может разрешить MessagePack и CSV - формат выбирается по Accept.
+package response
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/reqctx"
+	"go.uber.org/zap"
+)
+
+// Options - настройки ответов, общие для всех обработчиков
+type Options struct {
+	// Pretty - JSON с отступами (окружение development)
+	Pretty bool
+	// Logger - журнал ошибок кодирования и записи ответа
+	Logger *zap.Logger
+}
+
+type optionsKey struct{}
+
+// Configure - middleware, передающее Options хелперам ответа через контекст запроса.
+// Без него ответы компактные, а ошибки кодирования не пишутся в журнал.
+func Configure(opts Options) func(http.Handler) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), optionsKey{}, opts)))
+		})
+	}
+}
+
+func optionsFrom(ctx context.Context) Options {
+	opts, ok := ctx.Value(optionsKey{}).(Options)
+	if !ok {
+		opts.Logger = zap.NewNop()
+	}
+	return opts
+}
+
+// JSON отвечает v в JSON со статусом status
+func JSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	write(w, r, status, v, JSONFormat)
+}
+
+// Respond отвечает v со статусом status в формате, выбранном по Accept
+// из JSON и formats (MessagePack, CSV). JSON - формат по умолчанию; если
+// клиент не принимает ни один из форматов, ответ - 406.
+func Respond(w http.ResponseWriter, r *http.Request, status int, v interface{}, formats ...Format) {
+	format, ok := negotiate(r, formats)
+	if !ok {
+		notAcceptable(w, r, formats)
+		return
+	}
+	respondAs(w, r, status, v, format, formats)
+}
+
+// Created отвечает 201 с заголовком Location созданного ресурса
+func Created(w http.ResponseWriter, r *http.Request, location string, v interface{}, formats ...Format) {
+	format, ok := negotiate(r, formats)
+	if !ok {
+		notAcceptable(w, r, formats)
+		return
+	}
+	if location != "" {
+		w.Header().Set("Location", location)
+	}
+	respondAs(w, r, http.StatusCreated, v, format, formats)
+}
+
+// NoContent отвечает 204 без тела
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notAcceptable отвечает 406, если клиент не принимает ни один из форматов.
+// Вызывается до установки заголовков успешного ответа, чтобы они не попали в 406.
+func notAcceptable(w http.ResponseWriter, r *http.Request, formats []Format) {
+	apperrors.Write(w, r, apperrors.New(apperrors.KindNotAcceptable, "acceptable formats: %s", offered(formats)))
+}
+
+// respondAs отвечает в уже выбранном формате; Vary нужен, если форматов несколько
+func respondAs(w http.ResponseWriter, r *http.Request, status int, v interface{}, format Format, formats []Format) {
+	if len(formats) > 0 {
+		w.Header().Add("Vary", "Accept")
+	}
+	write(w, r, status, v, format)
+}
+
+// write кодирует v в буфер и только затем пишет заголовки и тело
+func write(w http.ResponseWriter, r *http.Request, status int, v interface{}, format Format) {
+	opts := optionsFrom(r.Context())
+
+	var buf bytes.Buffer
+	if err := format.Encode(&buf, v, opts); err != nil {
+		opts.Logger.Error("Failed to encode response",
+			zap.String("format", format.ContentType),
+			zap.String("path", r.URL.Path),
+			zap.String("request_id", reqctx.RequestID(r.Context())),
+			zap.Error(err),
+		)
+		apperrors.Write(w, r, apperrors.Internal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		// Обычно клиент закрыл соединение - ответ уже не доставить
+		opts.Logger.Debug("Failed to write response",
+			zap.String("path", r.URL.Path),
+			zap.String("request_id", reqctx.RequestID(r.Context())),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,157 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func get(target, accept string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	return r
+}
+
+func TestRespondNegotiation(t *testing.T) {
+	items := []item{{ID: 1, Name: "Anna"}}
+	tests := []struct {
+		name        string
+		accept      string
+		formats     []Format
+		status      int
+		contentType string
+	}{
+		{"json by default", "", []Format{CSV}, http.StatusOK, "application/json"},
+		{"json only ignores accept", "text/csv", nil, http.StatusOK, "application/json"},
+		{"csv", "text/csv", []Format{CSV}, http.StatusOK, CSV.ContentType},
+		{"quality order", "application/json;q=0.5, text/csv", []Format{CSV}, http.StatusOK, CSV.ContentType},
+		{"wildcard prefers handler order", "*/*", []Format{CSV}, http.StatusOK, "application/json"},
+		{"not acceptable", "application/xml", []Format{CSV}, http.StatusNotAcceptable, "application/problem+json"},
+		{"excluded by q=0", "text/csv;q=0, application/json;q=0", []Format{CSV}, http.StatusNotAcceptable, "application/problem+json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Respond(rec, get("/items", tt.accept), http.StatusOK, items, tt.formats...)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", ct, tt.contentType)
+			}
+			if tt.status == http.StatusOK && len(tt.formats) > 0 && rec.Header().Get("Vary") != "Accept" {
+				t.Fatalf("Vary = %q, want Accept", rec.Header().Get("Vary"))
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	Respond(rec, get("/items", "text/csv"), http.StatusOK, items, CSV)
+	if body := rec.Body.String(); body != "id,name\n1,Anna\n" {
+		t.Fatalf("csv body = %q", body)
+	}
+}
+
+func TestCreatedNotAcceptable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Created(rec, get("/items", "application/xml"), "/items/1", item{ID: 1}, CSV)
+	if rec.Code != http.StatusNotAcceptable || rec.Header().Get("Location") != "" {
+		t.Fatalf("status %d, Location %q; want 406 without Location", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec = httptest.NewRecorder()
+	Created(rec, get("/items", ""), "/items/1", item{ID: 1})
+	if rec.Code != http.StatusCreated || rec.Header().Get("Location") != "/items/1" {
+		t.Fatalf("status %d, Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestPaginated(t *testing.T) {
+	items := []item{{ID: 3, Name: "Anna"}, {ID: 4, Name: "Boris"}}
+
+	rec := httptest.NewRecorder()
+	Paginated(rec, get("/items?page=2&per_page=2&q=a", ""), items, Page{Page: 2, PerPage: 2, Total: 5}, CSV)
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Total-Count") != "5" {
+		t.Fatalf("status %d, X-Total-Count %q", rec.Code, rec.Header().Get("X-Total-Count"))
+	}
+	var body PageResponse[item]
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 || body.Pagination != (Pagination{Page: 2, PerPage: 2, Total: 5, TotalPages: 3}) {
+		t.Fatalf("body = %+v", body)
+	}
+
+	// В CSV - только элементы, сведения о странице остаются в заголовках
+	rec = httptest.NewRecorder()
+	Paginated(rec, get("/items?page=2&per_page=2", "text/csv"), items, Page{Page: 2, PerPage: 2, Total: 5}, CSV)
+	if body := rec.Body.String(); body != "id,name\n3,Anna\n4,Boris\n" || rec.Header().Get("X-Total-Count") != "5" {
+		t.Fatalf("csv body = %q, headers %v", body, rec.Header())
+	}
+
+	// Заголовки страницы не попадают в ответ 406
+	rec = httptest.NewRecorder()
+	Paginated(rec, get("/items?page=2&per_page=2", "application/xml"), items, Page{Page: 2, PerPage: 2, Total: 5}, CSV)
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want 406", rec.Code)
+	}
+	if rec.Header().Get("X-Total-Count") != "" || rec.Header().Get("Link") != "" {
+		t.Fatalf("406 has page headers: %v", rec.Header())
+	}
+
+	// nil отдаётся пустым массивом
+	rec = httptest.NewRecorder()
+	Paginated[item](rec, get("/items", ""), nil, Page{Page: 1, PerPage: 20})
+	if !strings.HasPrefix(rec.Body.String(), `{"items":[],`) {
+		t.Fatalf("body = %s", rec.Body)
+	}
+}
+
+func TestLinkHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		page Page
+		want []string
+	}{
+		{"first page", Page{Page: 1, PerPage: 10, Total: 25}, []string{
+			`</items?page=1&per_page=10&q=a>; rel="first"`,
+			`</items?page=2&per_page=10&q=a>; rel="next"`,
+			`</items?page=3&per_page=10&q=a>; rel="last"`,
+		}},
+		{"middle page", Page{Page: 2, PerPage: 10, Total: 25}, []string{
+			`</items?page=1&per_page=10&q=a>; rel="first"`,
+			`</items?page=1&per_page=10&q=a>; rel="prev"`,
+			`</items?page=3&per_page=10&q=a>; rel="next"`,
+			`</items?page=3&per_page=10&q=a>; rel="last"`,
+		}},
+		{"last page", Page{Page: 3, PerPage: 10, Total: 25}, []string{
+			`</items?page=1&per_page=10&q=a>; rel="first"`,
+			`</items?page=2&per_page=10&q=a>; rel="prev"`,
+			`</items?page=3&per_page=10&q=a>; rel="last"`,
+		}},
+		{"single page", Page{Page: 1, PerPage: 10, Total: 10}, []string{
+			`</items?page=1&per_page=10&q=a>; rel="first"`,
+			`</items?page=1&per_page=10&q=a>; rel="last"`,
+		}},
+		{"empty", Page{Page: 1, PerPage: 10, Total: 0}, nil},
+		{"no page size", Page{Page: 1, Total: 25}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Paginated[item](rec, get("/items?page=9&per_page=99&q=a", ""), nil, tt.page)
+			if got, want := rec.Header().Get("Link"), strings.Join(tt.want, ", "); got != want {
+				t.Fatalf("Link = %q\nwant %q", got, want)
+			}
+		})
+	}
+}
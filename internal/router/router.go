@@ -1,47 +1,121 @@
 package router
 
 import (
-	"fmt"
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/config"
 	"github.com/SmirnovND/gobase/internal/container"
 	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/SmirnovND/gobase/internal/middleware"
+	"github.com/SmirnovND/gobase/internal/module"
+	"github.com/SmirnovND/gobase/internal/ratelimit"
+	"github.com/SmirnovND/gobase/internal/response"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.uber.org/zap"
 	"net/http"
 )
 
-func Handler(diContainer *container.Container) http.Handler {
-	var healthcheckController interfaces.HealthcheckController
-	err := diContainer.Invoke(func(
-		d *sqlx.DB,
-		c interfaces.ConfigServer,
-		ctrl interfaces.HealthcheckController,
-	) {
-		healthcheckController = ctrl
+func Handler(diContainer *container.Container) (http.Handler, error) {
+	// Контроллеры всех включённых модулей (internal/modules)
+	var controllers []interfaces.Controller
+	var cf interfaces.ConfigServer
+	var logger *zap.Logger
+	err := diContainer.Invoke(func(in module.ControllersIn, c interfaces.ConfigServer, l *zap.Logger) {
+		controllers = in.Controllers
+		cf = c
+		logger = l
 	})
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return nil, err
+	}
+
+	limiter, err := rateLimiter(diContainer, cf, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticators, err := newAuthenticators(diContainer, cf)
+	if err != nil {
+		return nil, err
 	}
 
 	r := chi.NewRouter()
-	r.Use(middleware.StripSlashes)
+	// Стек middleware из блока middleware конфигурации (internal/middleware.Stack)
+	r.Use(middleware.Stack(cf, logger, limiter, authenticators)...)
+	r.Use(chimiddleware.StripSlashes)
+	// Настройки хелперов internal/response: отступы в JSON в development, журнал ошибок кодирования
+	r.Use(response.Configure(response.Options{Pretty: cf.IsDevelopment(), Logger: logger}))
 
-	r.Get("/ping", healthcheckController.HandlePing)
+	// Каждый контроллер - в своей группе, чтобы его r.Use не затрагивал чужие маршруты
+	for _, ctrl := range controllers {
+		r.Group(ctrl.RegisterRoutes)
+	}
 
 	// Swagger UI
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
-	// Обработчик для неподходящего метода (405 Method Not Allowed)
-	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	})
+	// 405 и 404 в том же формате application/problem+json, что и ошибки контроллеров
+	r.MethodNotAllowed(apperrors.MethodNotAllowedHandler)
+	r.NotFound(apperrors.NotFoundHandler)
 
-	// Обработчик для несуществующих маршрутов (404 Not Found)
-	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		http.Error(w, "Route not found", http.StatusNotFound)
-	})
+	return r, nil
+}
+
+// newAuthenticators - схемы заголовка Authorization из блока auth конфигурации.
+// Схему ApiKey проверяет сервис модуля apikeys, он запрашивается из контейнера
+// только при auth.api_key.enabled
+func newAuthenticators(diContainer *container.Container, cf interfaces.ConfigServer) ([]auth.Authenticator, error) {
+	var authenticators []auth.Authenticator
+	if cf.GetJWTEnabled() {
+		jwt, err := auth.NewJWTAuthenticator(cf.GetJWTConfig())
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, jwt)
+	}
+	if cf.GetAPIKeyEnabled() {
+		if err := diContainer.Invoke(func(keys interfaces.APIKeyService) {
+			authenticators = append(authenticators, keys)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return authenticators, nil
+}
 
-	return r
+// rateLimiter - rate limiting для middleware.rate_limit. *sqlx.DB запрашивается
+// из контейнера только для store: postgres. Лимиты и источники ключа
+// обновляются при перезагрузке конфигурации (SIGHUP)
+func rateLimiter(diContainer *container.Container, cf interfaces.ConfigServer, logger *zap.Logger) (*middleware.RateLimiter, error) {
+	if !cf.GetRateLimitEnabled() {
+		return nil, nil
+	}
+	algorithm, err := ratelimit.AlgorithmByName(cf.GetRateLimitAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	store := ratelimit.NewMemoryStore()
+	if cf.GetRateLimitStore() == ratelimit.StorePostgres {
+		if err := diContainer.Invoke(func(db *sqlx.DB) {
+			store = ratelimit.NewPostgresStore(db)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	rl := middleware.NewRateLimiter(ratelimit.New(algorithm, store), cf, logger)
+	err = diContainer.Invoke(func(reloader *config.Reloader) {
+		reloader.Subscribe(func(next interfaces.ConfigCommon) {
+			if m, ok := next.(interfaces.ConfigMiddleware); ok {
+				rl.Update(m)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rl, nil
 }
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// lastUsedInterval - last_used_at обновляется не чаще, чтобы частые вызовы
+// одного ключа не превращались в запись на каждый запрос
+const lastUsedInterval = time.Minute
+
+type apiKeyService struct {
+	repo   interfaces.APIKeyRepository
+	logger *zap.Logger
+}
+
+func NewAPIKeyService(repo interfaces.APIKeyRepository, logger *zap.Logger) interfaces.APIKeyService {
+	return &apiKeyService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *apiKeyService) Scheme() string {
+	return auth.SchemeAPIKey
+}
+
+// Authenticate ищет ключ по хешу: неизвестный, отозванный и истёкший ключ, а также
+// прежний секрет после окончания периода ротации - 401.
+// Субъект - ID ключа (Kind auth.KindAPIKey), роли выдаются привязками RBAC.
+func (s *apiKeyService) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	hash := auth.HashAPIKey(credentials)
+	key, err := s.repo.GetByHash(ctx, hash)
+	if err != nil {
+		if apperrors.As(err).Kind == apperrors.KindNotFound {
+			return nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("find api key: %w", err)
+	}
+	now := time.Now()
+	if !key.Accepts(hash, now) {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.logger.Warn("Failed to update api key last use", zap.Int64("api_key_id", key.ID), zap.Error(err))
+		}
+	}
+
+	p := &auth.Principal{
+		Kind:    auth.KindAPIKey,
+		Subject: strconv.FormatInt(key.ID, 10),
+		Scopes:  key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		p.ExpiresAt = *key.ExpiresAt
+	}
+	return p, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, filter domain.APIKeyFilter) ([]domain.APIKey, int64, error) {
+	keys, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list api keys: %w", err)
+	}
+	return keys, total, nil
+}
+
+func (s *apiKeyService) Get(ctx context.Context, id int64) (*domain.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get api key %d: %w", id, err)
+	}
+	return key, nil
+}
+
+// Create выпускает ключ; создатель (субъект из контекста) сохраняется в created_by
+func (s *apiKeyService) Create(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*domain.IssuedAPIKey, error) {
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate api key: %w", err)
+	}
+	key := domain.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		key.CreatedBy = p.Kind + ":" + p.Subject
+	}
+	if err := s.repo.Create(ctx, &key); err != nil {
+		return nil, fmt.Errorf("create api key %s: %w", name, err)
+	}
+	return &domain.IssuedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// Rotate выпускает новый секрет. Отозванный и истёкший ключ - 409: продлевать
+// срок ротацией нельзя, для такого клиента выпускается новый ключ.
+func (s *apiKeyService) Rotate(ctx context.Context, id int64, gracePeriod time.Duration) (*domain.IssuedAPIKey, error) {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("rotate api key %d: %w", id, err)
+	}
+	if current.RevokedAt != nil {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+	if current.Expired(time.Now()) {
+		return nil, domain.ErrAPIKeyExpired
+	}
+
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate api key: %w", err)
+	}
+	key, err := s.repo.Rotate(ctx, id, prefix, hash, gracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("rotate api key %d: %w", id, err)
+	}
+	return &domain.IssuedAPIKey{APIKey: *key, Key: secret}, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id int64) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return fmt.Errorf("revoke api key %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// apiKeyRepo хранит один ключ в памяти; Rotate повторяет UPDATE репозитория
+type apiKeyRepo struct {
+	interfaces.APIKeyRepository
+	key domain.APIKey
+}
+
+func (r *apiKeyRepo) GetByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	key := r.key
+	return &key, nil
+}
+
+func (r *apiKeyRepo) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	if r.key.Hash != hash && (r.key.PreviousHash == nil || *r.key.PreviousHash != hash) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	key := r.key
+	return &key, nil
+}
+
+func (r *apiKeyRepo) Rotate(ctx context.Context, id int64, prefix, hash string, gracePeriod time.Duration) (*domain.APIKey, error) {
+	r.key.PreviousHash, r.key.PreviousExpiresAt = nil, nil
+	if gracePeriod > 0 {
+		previous, expiresAt := r.key.Hash, time.Now().Add(gracePeriod)
+		r.key.PreviousHash, r.key.PreviousExpiresAt = &previous, &expiresAt
+	}
+	r.key.Prefix, r.key.Hash = prefix, hash
+	key := r.key
+	return &key, nil
+}
+
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	return nil
+}
+
+func TestAPIKeyRotateGracePeriod(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		gracePeriod time.Duration
+		oldAccepted bool
+	}{
+		{name: "immediate", gracePeriod: 0, oldAccepted: false},
+		{name: "grace period", gracePeriod: time.Hour, oldAccepted: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &apiKeyRepo{key: domain.APIKey{ID: 17, Hash: auth.HashAPIKey("old")}}
+			svc := NewAPIKeyService(repo, zap.NewNop())
+
+			issued, err := svc.Rotate(context.Background(), 17, tt.gracePeriod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.Authenticate(context.Background(), issued.Key); err != nil {
+				t.Fatalf("new key: %v", err)
+			}
+			_, err = svc.Authenticate(context.Background(), "old")
+			if tt.oldAccepted && err != nil {
+				t.Fatalf("old key within grace period: %v", err)
+			}
+			if !tt.oldAccepted && !errors.Is(err, domain.ErrAPIKeyInvalid) {
+				t.Fatalf("old key = %v, want invalid", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyPreviousSecretExpires(t *testing.T) {
+	previous, expired := auth.HashAPIKey("old"), time.Now().Add(-time.Second)
+	repo := &apiKeyRepo{key: domain.APIKey{ID: 17, Hash: auth.HashAPIKey("new"), PreviousHash: &previous, PreviousExpiresAt: &expired}}
+	svc := NewAPIKeyService(repo, zap.NewNop())
+	if _, err := svc.Authenticate(context.Background(), "old"); !errors.Is(err, domain.ErrAPIKeyInvalid) {
+		t.Fatalf("old key after grace period = %v, want invalid", err)
+	}
+}
+
+func TestAPIKeyRotateExpired(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Minute)
+	repo := &apiKeyRepo{key: domain.APIKey{ID: 17, Hash: auth.HashAPIKey("old"), ExpiresAt: &expiresAt}}
+	svc := NewAPIKeyService(repo, zap.NewNop())
+	if _, err := svc.Rotate(context.Background(), 17, time.Hour); !errors.Is(err, domain.ErrAPIKeyExpired) {
+		t.Fatalf("Rotate expired key = %v, want api_key_expired", err)
+	}
+}
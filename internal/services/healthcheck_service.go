@@ -2,27 +2,348 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"github.com/SmirnovND/gobase/internal/domain"
 	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+	"sync"
+	"time"
 )
 
+// shutdownCheck - имя проверки, добавляемой в readiness после Drain
+const shutdownCheck = "shutdown"
+
+// staleCheck - имя проверки, добавляемой в readiness и startup, когда
+// фоновое обновление не обновляло результаты дольше допустимого
+const staleCheck = "refresh"
+
+// HealthcheckOptions - параметры сервиса health проверок (блок health конфигурации)
+type HealthcheckOptions struct {
+	// CheckTimeout - таймаут проверок, у которых не задан собственный Timeout
+	CheckTimeout time.Duration
+	// RefreshInterval - период фонового обновления (Run), 0 - проверки на каждый запрос
+	RefreshInterval time.Duration
+	// MaxAgeIntervals - через сколько периодов RefreshInterval сохранённый результат
+	// устаревает и readiness и startup пробы отвечают down. 0 - без ограничения
+	MaxAgeIntervals int
+	// FreshInterval - не чаще какого периода CheckFresh выполняет проверки пробы,
+	// остальные вызовы получают последний результат. 0 - без ограничения
+	FreshInterval time.Duration
+}
+
+// lastError - последняя ошибка проверки, сохраняется и после восстановления компонента
+type lastError struct {
+	message string
+	at      time.Time
+}
+
 type healthcheckService struct {
-	healthRepo interfaces.HealthcheckRepository
+	checks          []interfaces.HealthCheck
+	defaultTimeout  time.Duration
+	refreshInterval time.Duration
+	maxAge          time.Duration
+	freshInterval   time.Duration
+	logger          *zap.Logger
+
+	mu          sync.Mutex
+	draining    bool
+	results     map[string]domain.HealthCheckResult
+	lastErrors  map[string]lastError
+	lastFresh   map[domain.Probe]time.Time
+	subscribers []func(domain.HealthTransition)
 }
 
-func NewHealthcheckService(healthRepo interfaces.HealthcheckRepository) interfaces.HealthcheckService {
+// NewHealthcheckService создаёт сервис health проверок с параметрами opts
+func NewHealthcheckService(checks []interfaces.HealthCheck, opts HealthcheckOptions, logger *zap.Logger) interfaces.HealthcheckService {
 	return &healthcheckService{
-		healthRepo: healthRepo,
+		checks:          checks,
+		defaultTimeout:  opts.CheckTimeout,
+		refreshInterval: opts.RefreshInterval,
+		maxAge:          time.Duration(opts.MaxAgeIntervals) * opts.RefreshInterval,
+		freshInterval:   opts.FreshInterval,
+		logger:          logger,
+		results:         make(map[string]domain.HealthCheckResult),
+		lastErrors:      make(map[string]lastError),
+		lastFresh:       make(map[domain.Probe]time.Time),
+	}
+}
+
+// Check возвращает результаты последнего фонового обновления, поэтому частые
+// запросы проб не нагружают зависимости. Пока фоновое обновление не выполнило
+// все проверки пробы (или оно отключено), проверки выполняются по запросу.
+func (s *healthcheckService) Check(ctx context.Context, probe domain.Probe) domain.HealthReport {
+	checks := s.probeChecks(probe)
+	if s.refreshInterval > 0 {
+		if results, ok := s.cached(checks); ok {
+			return s.report(probe, results, true)
+		}
+	}
+	return s.report(probe, s.runChecks(ctx, checks), false)
+}
+
+// CheckFresh выполняет проверки пробы параллельно, каждую со своим таймаутом,
+// поэтому зависшая зависимость не задерживает ответ дольше таймаута.
+// Результаты обновляют кеш, как и фоновые. Проверки пробы выполняются не чаще
+// freshInterval, сколько бы клиентов ни запрашивали ?fresh=1: в промежутке
+// возвращается последний сохранённый результат.
+func (s *healthcheckService) CheckFresh(ctx context.Context, probe domain.Probe) domain.HealthReport {
+	checks := s.probeChecks(probe)
+	if !s.allowFresh(probe) {
+		if results, ok := s.cached(checks); ok {
+			return s.report(probe, results, true)
+		}
+	}
+	return s.report(probe, s.runChecks(ctx, checks), false)
+}
+
+// allowFresh резервирует выполнение проверок пробы по запросу, если с прошлого прошло freshInterval
+func (s *healthcheckService) allowFresh(probe domain.Probe) bool {
+	if s.freshInterval <= 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastFresh[probe]) < s.freshInterval {
+		return false
+	}
+	s.lastFresh[probe] = now
+	return true
+}
+
+// Drain переводит readiness пробу в down с проверкой shutdown, чтобы балансировщик
+// перестал направлять запросы до остановки HTTP сервера. Liveness не меняется.
+func (s *healthcheckService) Drain() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		return
+	}
+	s.draining = true
+	s.logger.Info("Readiness drain started")
+}
+
+// Subscribe регистрирует обработчик смены состояния проверок.
+// Обработчик вызывается синхронно из горутины проверки и не должен блокироваться.
+func (s *healthcheckService) Subscribe(fn func(domain.HealthTransition)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Run выполняет все проверки сразу и затем с периодом refreshInterval до отмены ctx
+func (s *healthcheckService) Run(ctx context.Context) {
+	if s.refreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		s.runChecks(ctx, s.checks)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *healthcheckService) probeChecks(probe domain.Probe) []interfaces.HealthCheck {
+	var checks []interfaces.HealthCheck
+	for _, check := range s.checks {
+		if hasProbe(check, probe) {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// cached возвращает сохранённые результаты checks, если они есть для всех проверок
+func (s *healthcheckService) cached(checks []interfaces.HealthCheck) ([]domain.HealthCheckResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]domain.HealthCheckResult, 0, len(checks))
+	for _, check := range checks {
+		result, ok := s.results[check.Name]
+		if !ok {
+			return nil, false
+		}
+		results = append(results, result)
+	}
+	return results, true
+}
+
+func (s *healthcheckService) runChecks(ctx context.Context, checks []interfaces.HealthCheck) []domain.HealthCheckResult {
+	results := make([]domain.HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check interfaces.HealthCheck) {
+			defer wg.Done()
+			results[i] = s.record(s.run(ctx, check))
+		}(i, check)
 	}
+	wg.Wait()
+	return results
 }
 
-// Check проверяет здоровье сервиса и базы данных
-func (s *healthcheckService) Check(ctx context.Context) (map[string]interface{}, error) {
-	err := s.healthRepo.Ping(ctx)
+// run выполняет одну проверку. Проверка, не уважающая контекст, продолжает
+// работать в фоне, но результат пробы возвращается по истечении таймаута.
+func (s *healthcheckService) run(ctx context.Context, check interfaces.HealthCheck) domain.HealthCheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = s.defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := domain.HealthCheckResult{
+		Name:      check.Name,
+		Status:    domain.HealthUp,
+		Required:  check.Required,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now(),
+	}
 	if err != nil {
-		return nil, err
+		result.Status = domain.HealthDegraded
+		if check.Required {
+			result.Status = domain.HealthDown
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// record дополняет результат последней ошибкой, сохраняет его в кеш
+// и сообщает о смене состояния проверки
+func (s *healthcheckService) record(result domain.HealthCheckResult) domain.HealthCheckResult {
+	s.mu.Lock()
+	if result.Error != "" {
+		s.lastErrors[result.Name] = lastError{message: result.Error, at: result.CheckedAt}
+	}
+	if last, ok := s.lastErrors[result.Name]; ok {
+		at := last.at
+		result.LastError = last.message
+		result.LastErrorAt = &at
+	}
+
+	// Первый результат - переход из up: об успешном старте не сообщаем
+	from := domain.HealthUp
+	if prev, ok := s.results[result.Name]; ok {
+		from = prev.Status
+	}
+	s.results[result.Name] = result
+	subscribers := s.subscribers
+	s.mu.Unlock()
+
+	if from == result.Status {
+		return result
+	}
+	transition := domain.HealthTransition{
+		Name:  result.Name,
+		From:  from,
+		To:    result.Status,
+		Error: result.Error,
+		At:    result.CheckedAt,
+	}
+	s.logTransition(transition)
+	for _, fn := range subscribers {
+		fn(transition)
+	}
+	return result
+}
+
+func (s *healthcheckService) logTransition(t domain.HealthTransition) {
+	fields := []zap.Field{
+		zap.String("check", t.Name),
+		zap.String("from", string(t.From)),
+		zap.String("to", string(t.To)),
+	}
+	switch t.To {
+	case domain.HealthUp:
+		s.logger.Info("Health check recovered", fields...)
+	case domain.HealthDegraded:
+		s.logger.Warn("Health check degraded", append(fields, zap.String("error", t.Error))...)
+	default:
+		s.logger.Error("Health check down", append(fields, zap.String("error", t.Error))...)
+	}
+}
+
+// report собирает отчёт пробы: down, если не прошла обязательная проверка;
+// degraded - если необязательная
+func (s *healthcheckService) report(probe domain.Probe, results []domain.HealthCheckResult, cached bool) domain.HealthReport {
+	now := time.Now()
+
+	s.mu.Lock()
+	draining := s.draining
+	s.mu.Unlock()
+	if draining && probe == domain.ProbeReadiness {
+		results = append(results, domain.HealthCheckResult{
+			Name:      shutdownCheck,
+			Status:    domain.HealthDown,
+			Required:  true,
+			Error:     "shutting down",
+			CheckedAt: now,
+		})
 	}
 
-	return map[string]interface{}{
-		"status": "ok",
-	}, nil
+	var oldest time.Duration
+	for _, result := range results {
+		if age := now.Sub(result.CheckedAt); age > oldest {
+			oldest = age
+		}
+	}
+	// Фоновое обновление остановилось или зависло: сохранённым результатам нельзя верить
+	if cached && s.maxAge > 0 && oldest > s.maxAge && probe != domain.ProbeLiveness {
+		results = append(results, domain.HealthCheckResult{
+			Name:      staleCheck,
+			Status:    domain.HealthDown,
+			Required:  true,
+			Error:     fmt.Sprintf("results are %s old, want at most %s", oldest.Round(time.Millisecond), s.maxAge),
+			CheckedAt: now,
+		})
+	}
+
+	r := domain.HealthReport{
+		Probe:     probe,
+		Status:    domain.HealthUp,
+		Checks:    results,
+		CheckedAt: now,
+		Cached:    cached,
+		AgeMs:     float64(oldest.Microseconds()) / 1000,
+	}
+	for _, result := range results {
+		if result.Status == domain.HealthUp {
+			continue
+		}
+		if result.Required {
+			r.Status = domain.HealthDown
+		} else if r.Status == domain.HealthUp {
+			r.Status = domain.HealthDegraded
+		}
+	}
+	return r
+}
+
+func hasProbe(check interfaces.HealthCheck, probe domain.Probe) bool {
+	for _, p := range check.Probes {
+		if p == probe {
+			return true
+		}
+	}
+	return false
 }
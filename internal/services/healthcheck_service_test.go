@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+func countingCheck(runs *atomic.Int32) []interfaces.HealthCheck {
+	return []interfaces.HealthCheck{{
+		Name:     "postgres",
+		Probes:   []domain.Probe{domain.ProbeLiveness, domain.ProbeReadiness},
+		Required: true,
+		Check: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}}
+}
+
+func TestCheckFreshIsRateLimited(t *testing.T) {
+	var runs atomic.Int32
+	svc := NewHealthcheckService(countingCheck(&runs), HealthcheckOptions{
+		CheckTimeout:    time.Second,
+		RefreshInterval: time.Minute,
+		FreshInterval:   time.Minute,
+	}, zap.NewNop())
+
+	for i := 0; i < 10; i++ {
+		if report := svc.CheckFresh(context.Background(), domain.ProbeReadiness); report.Status != domain.HealthUp {
+			t.Fatalf("status = %s, want up", report.Status)
+		}
+	}
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("checks ran %d times, want 1 per fresh_interval", n)
+	}
+
+	// Ограничение действует на каждую пробу отдельно
+	if report := svc.CheckFresh(context.Background(), domain.ProbeLiveness); report.Cached {
+		t.Fatal("first liveness fresh check must not be cached")
+	}
+}
+
+func TestCheckReportsStaleResults(t *testing.T) {
+	var runs atomic.Int32
+	svc := NewHealthcheckService(countingCheck(&runs), HealthcheckOptions{
+		CheckTimeout:    time.Second,
+		RefreshInterval: 10 * time.Millisecond,
+		MaxAgeIntervals: 2,
+	}, zap.NewNop())
+
+	// Результат сохранён, но фоновое обновление (Run) не запущено
+	svc.CheckFresh(context.Background(), domain.ProbeReadiness)
+	if report := svc.Check(context.Background(), domain.ProbeReadiness); report.Status != domain.HealthUp {
+		t.Fatalf("fresh result: status = %s, want up", report.Status)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	report := svc.Check(context.Background(), domain.ProbeReadiness)
+	if report.Status != domain.HealthDown {
+		t.Fatalf("stale result: status = %s, want down", report.Status)
+	}
+	last := report.Checks[len(report.Checks)-1]
+	if last.Name != staleCheck {
+		t.Fatalf("last check = %s, want %s", last.Name, staleCheck)
+	}
+
+	// Liveness не зависит от фонового обновления
+	if report := svc.Check(context.Background(), domain.ProbeLiveness); report.Status != domain.HealthUp {
+		t.Fatalf("liveness: status = %s, want up", report.Status)
+	}
+}
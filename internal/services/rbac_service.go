@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// cachedPermissions - разрешения субъекта и момент, после которого их нужно перечитать
+type cachedPermissions struct {
+	permissions []domain.Permission
+	expiresAt   time.Time
+}
+
+// RBACOptions - параметры сервиса RBAC (блок rbac конфигурации)
+type RBACOptions struct {
+	// CacheTTL - сколько хранятся разрешения субъекта, 0 - без кеша
+	CacheTTL time.Duration
+	// TrustTokenRoles - учитывать роли из Principal.Roles (claim roles токена).
+	// Включать только если роли в токенах выдаёт доверенный издатель.
+	TrustTokenRoles bool
+}
+
+type rbacService struct {
+	repo interfaces.RBACRepository
+	opts RBACOptions
+
+	mu    sync.Mutex
+	cache map[string]cachedPermissions
+	// generation растёт при каждом сбросе кеша: загрузка, начатая до сброса,
+	// не кладёт в кеш прочитанные до изменения разрешения
+	generation uint64
+}
+
+// NewRBACService создаёт сервис RBAC. Разрешения субъектов кешируются на opts.CacheTTL;
+// изменения ролей и привязок через сервис сбрасывают кеш сразу, изменения
+// на других репликах становятся видны по истечении CacheTTL.
+func NewRBACService(repo interfaces.RBACRepository, opts RBACOptions) interfaces.RBACService {
+	return &rbacService{
+		repo:  repo,
+		opts:  opts,
+		cache: make(map[string]cachedPermissions),
+	}
+}
+
+// Authorize проверяет разрешения ролей субъекта: привязанных в БД
+// и, при TrustTokenRoles, перечисленных в Principal.Roles (claim roles токена)
+func (s *rbacService) Authorize(ctx context.Context, action, resource string) error {
+	p, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	permissions, err := s.permissions(ctx, p)
+	if err != nil {
+		return fmt.Errorf("load permissions of %s %s: %w", p.Kind, p.Subject, err)
+	}
+	for _, perm := range permissions {
+		if perm.Allows(action, resource) {
+			return nil
+		}
+	}
+	return domain.ErrPermissionDenied
+}
+
+func (s *rbacService) permissions(ctx context.Context, p *auth.Principal) ([]domain.Permission, error) {
+	var roles []string
+	if s.opts.TrustTokenRoles {
+		roles = append(roles, p.Roles...)
+		sort.Strings(roles)
+	}
+	key := p.Kind + "|" + p.Subject + "|" + strings.Join(roles, ",")
+
+	now := time.Now()
+	var generation uint64
+	if s.opts.CacheTTL > 0 {
+		s.mu.Lock()
+		cached, ok := s.cache[key]
+		generation = s.generation
+		s.mu.Unlock()
+		if ok && now.Before(cached.expiresAt) {
+			return cached.permissions, nil
+		}
+	}
+
+	permissions, err := s.repo.SubjectPermissions(ctx, p.Kind, p.Subject, roles)
+	if err != nil {
+		return nil, err
+	}
+	if s.opts.CacheTTL > 0 {
+		s.mu.Lock()
+		if s.generation == generation {
+			s.sweep(now)
+			s.cache[key] = cachedPermissions{permissions: permissions, expiresAt: now.Add(s.opts.CacheTTL)}
+		}
+		s.mu.Unlock()
+	}
+	return permissions, nil
+}
+
+// sweep удаляет истёкшие записи, чтобы кеш не рос с числом субъектов. Вызывается под mu.
+func (s *rbacService) sweep(now time.Time) {
+	for key, cached := range s.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// invalidate сбрасывает кеш после изменения ролей, разрешений или привязок
+func (s *rbacService) invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string]cachedPermissions)
+	s.generation++
+	s.mu.Unlock()
+}
+
+func (s *rbacService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (s *rbacService) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	role, err := s.repo.GetRole(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get role %s: %w", name, err)
+	}
+	return role, nil
+}
+
+func (s *rbacService) CreateRole(ctx context.Context, role *domain.Role) error {
+	if err := s.repo.CreateRole(ctx, role); err != nil {
+		return fmt.Errorf("create role %s: %w", role.Name, err)
+	}
+	return nil
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, name string) error {
+	defer s.invalidate()
+	if err := s.repo.DeleteRole(ctx, name); err != nil {
+		return fmt.Errorf("delete role %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *rbacService) AddPermission(ctx context.Context, role string, p *domain.Permission) error {
+	defer s.invalidate()
+	if err := s.repo.CreatePermission(ctx, role, p); err != nil {
+		return fmt.Errorf("add permission to role %s: %w", role, err)
+	}
+	return nil
+}
+
+func (s *rbacService) RemovePermission(ctx context.Context, role string, id int64) error {
+	defer s.invalidate()
+	if err := s.repo.DeletePermission(ctx, role, id); err != nil {
+		return fmt.Errorf("remove permission %d from role %s: %w", id, role, err)
+	}
+	return nil
+}
+
+func (s *rbacService) ListBindings(ctx context.Context, filter domain.RoleBindingFilter) ([]domain.RoleBinding, int64, error) {
+	bindings, total, err := s.repo.ListBindings(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list role bindings: %w", err)
+	}
+	return bindings, total, nil
+}
+
+func (s *rbacService) Bind(ctx context.Context, b *domain.RoleBinding) error {
+	defer s.invalidate()
+	if err := s.repo.CreateBinding(ctx, b); err != nil {
+		return fmt.Errorf("bind role %s to %s %s: %w", b.Role, b.SubjectKind, b.Subject, err)
+	}
+	return nil
+}
+
+func (s *rbacService) Unbind(ctx context.Context, id int64) error {
+	defer s.invalidate()
+	if err := s.repo.DeleteBinding(ctx, id); err != nil {
+		return fmt.Errorf("delete role binding %d: %w", id, err)
+	}
+	return nil
+}
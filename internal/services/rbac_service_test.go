@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SmirnovND/gobase/internal/auth"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+)
+
+// rbacRepo - разрешения роли admin; остальные методы RBACRepository не вызываются,
+// кроме CreateBinding. loading вызывается внутри SubjectPermissions до возврата результата.
+type rbacRepo struct {
+	interfaces.RBACRepository
+	bound   bool
+	roles   []string
+	loading func()
+}
+
+func (r *rbacRepo) SubjectPermissions(ctx context.Context, kind, subject string, roles []string) ([]domain.Permission, error) {
+	r.roles = roles
+	granted := r.bound
+	for _, role := range roles {
+		granted = granted || role == "admin"
+	}
+	if r.loading != nil {
+		r.loading()
+	}
+	if !granted {
+		return nil, nil
+	}
+	return []domain.Permission{{Action: "*", Resource: "*"}}, nil
+}
+
+func (r *rbacRepo) CreateBinding(ctx context.Context, b *domain.RoleBinding) error {
+	r.bound = true
+	return nil
+}
+
+func principalCtx(roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Kind: auth.KindUser, Subject: "42", Roles: roles})
+}
+
+func TestAuthorizeIgnoresTokenRoles(t *testing.T) {
+	repo := &rbacRepo{}
+	svc := NewRBACService(repo, RBACOptions{})
+	err := svc.Authorize(principalCtx("admin"), "manage", "rbac")
+	if !errors.Is(err, domain.ErrPermissionDenied) {
+		t.Fatalf("Authorize = %v, want permission denied", err)
+	}
+	if len(repo.roles) != 0 {
+		t.Fatalf("token roles passed to repository: %v", repo.roles)
+	}
+
+	svc = NewRBACService(&rbacRepo{}, RBACOptions{TrustTokenRoles: true})
+	if err := svc.Authorize(principalCtx("admin"), "manage", "rbac"); err != nil {
+		t.Fatalf("Authorize with trusted token roles = %v", err)
+	}
+}
+
+func TestAuthorizeCacheSkipsLoadRacingInvalidate(t *testing.T) {
+	repo := &rbacRepo{}
+	svc := NewRBACService(repo, RBACOptions{CacheTTL: time.Minute})
+
+	// Привязка выдаётся, пока загрузка ещё не вернула старые разрешения
+	repo.loading = func() {
+		repo.loading = nil
+		if err := svc.Bind(context.Background(), &domain.RoleBinding{SubjectKind: auth.KindUser, Subject: "42", Role: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Authorize(principalCtx(), "manage", "rbac"); !errors.Is(err, domain.ErrPermissionDenied) {
+		t.Fatalf("first Authorize = %v, want permission denied", err)
+	}
+
+	// Прочитанный до сброса отказ не закеширован
+	if err := svc.Authorize(principalCtx(), "manage", "rbac"); err != nil {
+		t.Fatalf("Authorize after bind = %v", err)
+	}
+}
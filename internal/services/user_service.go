@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+type userService struct {
+	userRepo interfaces.UserRepository
+}
+
+func NewUserService(userRepo interfaces.UserRepository) interfaces.UserService {
+	return &userService{
+		userRepo: userRepo,
+	}
+}
+
+func (s *userService) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list users: %w", err)
+	}
+	return users, total, nil
+}
+
+func (s *userService) Get(ctx context.Context, id int64) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user %d: %w", id, err)
+	}
+	return user, nil
+}
+
+func (s *userService) GetForUpdateTx(tx *sqlx.Tx, id int64) (*domain.User, error) {
+	user, err := s.userRepo.GetByIDForUpdateTx(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("lock user %d: %w", id, err)
+	}
+	return user, nil
+}
+
+func (s *userService) Create(ctx context.Context, user *domain.User) error {
+	normalizeUser(user)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("create user %s: %w", user.Email, err)
+	}
+	return nil
+}
+
+func (s *userService) CreateTx(tx *sqlx.Tx, user *domain.User) error {
+	normalizeUser(user)
+	if err := s.userRepo.CreateTx(tx, user); err != nil {
+		return fmt.Errorf("create user %s: %w", user.Email, err)
+	}
+	return nil
+}
+
+func (s *userService) UpdateTx(tx *sqlx.Tx, user *domain.User) error {
+	normalizeUser(user)
+	if err := s.userRepo.UpdateTx(tx, user); err != nil {
+		return fmt.Errorf("update user %d: %w", user.ID, err)
+	}
+	return nil
+}
+
+func (s *userService) Delete(ctx context.Context, id int64) error {
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete user %d: %w", id, err)
+	}
+	return nil
+}
+
+func (s *userService) DeleteTx(tx *sqlx.Tx, id int64) error {
+	if err := s.userRepo.DeleteTx(tx, id); err != nil {
+		return fmt.Errorf("delete user %d: %w", id, err)
+	}
+	return nil
+}
+
+// normalizeUser - email сравнивается без учёта регистра, поэтому хранится в нижнем
+func normalizeUser(user *domain.User) {
+	user.Name = strings.TrimSpace(user.Name)
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+}
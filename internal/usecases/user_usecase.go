@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// Действия и ресурс пользователей для Authorizer
+const (
+	userActionRead   = "read"
+	userActionCreate = "create"
+	userActionUpdate = "update"
+	userActionDelete = "delete"
+	userResource     = "users"
+)
+
+type userUsecase struct {
+	userService        interfaces.UserService
+	authz              interfaces.Authorizer
+	transactionManager interfaces.TransactionManager
+}
+
+func NewUserUsecase(
+	userService interfaces.UserService,
+	authz interfaces.Authorizer,
+	transactionManager interfaces.TransactionManager,
+) interfaces.UserUsecase {
+	return &userUsecase{
+		userService:        userService,
+		authz:              authz,
+		transactionManager: transactionManager,
+	}
+}
+
+func (uc *userUsecase) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	if err := uc.authz.Authorize(ctx, userActionRead, userResource); err != nil {
+		return nil, 0, err
+	}
+	return uc.userService.List(ctx, filter)
+}
+
+func (uc *userUsecase) Get(ctx context.Context, id int64) (*domain.User, error) {
+	if err := uc.authz.Authorize(ctx, userActionRead, userResourceID(id)); err != nil {
+		return nil, err
+	}
+	return uc.userService.Get(ctx, id)
+}
+
+func (uc *userUsecase) Create(ctx context.Context, name, email string) (*domain.User, error) {
+	if err := uc.authz.Authorize(ctx, userActionCreate, userResource); err != nil {
+		return nil, err
+	}
+	user := &domain.User{Name: name, Email: email}
+	if err := uc.userService.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Update читает пользователя с блокировкой строки и записывает изменённую копию
+// в одной транзакции: параллельный PATCH другого поля ждёт и не затирает изменение
+func (uc *userUsecase) Update(ctx context.Context, id int64, patch domain.UserPatch) (*domain.User, error) {
+	if err := uc.authz.Authorize(ctx, userActionUpdate, userResourceID(id)); err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	err := uc.transactionManager.Execute(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		if user, err = uc.userService.GetForUpdateTx(tx, id); err != nil {
+			return err
+		}
+		patch.Apply(user)
+		return uc.userService.UpdateTx(tx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *userUsecase) Delete(ctx context.Context, id int64) error {
+	if err := uc.authz.Authorize(ctx, userActionDelete, userResourceID(id)); err != nil {
+		return err
+	}
+	return uc.userService.Delete(ctx, id)
+}
+
+// userResourceID - ресурс конкретного пользователя: users:42
+func userResourceID(id int64) string {
+	return userResource + ":" + strconv.FormatInt(id, 10)
+}
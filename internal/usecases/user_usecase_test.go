@@ -0,0 +1,135 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/SmirnovND/gobase/internal/apperrors"
+	"github.com/SmirnovND/gobase/internal/container"
+	"github.com/SmirnovND/gobase/internal/domain"
+	"github.com/SmirnovND/gobase/internal/interfaces"
+	"github.com/jmoiron/sqlx"
+)
+
+// txRecorder - TransactionManager, который отмечает, что fn выполняется в транзакции
+type txRecorder struct {
+	active   bool
+	executed int
+}
+
+func (m *txRecorder) Execute(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	m.executed++
+	m.active = true
+	defer func() { m.active = false }()
+	return fn(nil)
+}
+
+// newUserUsecase собирает use case модуля users из контейнера: реальный сервис
+// поверх мока репозитория, права и транзакции - моки
+func newUserUsecase(t *testing.T, repo interfaces.UserRepository, authz interfaces.Authorizer, tm interfaces.TransactionManager) interfaces.UserUsecase {
+	return container.MustResolve[interfaces.UserUsecase](t,
+		container.WithOverride(func() interfaces.UserRepository { return repo }),
+		container.WithOverride(func() interfaces.Authorizer { return authz }),
+		container.WithOverride(func() interfaces.TransactionManager { return tm }),
+	)
+}
+
+func TestUserUsecase_DeniedIsForbidden(t *testing.T) {
+	touched := false
+	repo := &interfaces.MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (*domain.User, error) {
+			touched = true
+			return &domain.User{ID: id}, nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			touched = true
+			return nil
+		},
+	}
+	var checked []string
+	authz := &interfaces.MockAuthorizer{
+		AuthorizeFunc: func(ctx context.Context, action, resource string) error {
+			checked = append(checked, action+" "+resource)
+			return domain.ErrPermissionDenied
+		},
+	}
+	uc := newUserUsecase(t, repo, authz, &interfaces.MockTransactionManager{})
+
+	name := "New"
+	_, getErr := uc.Get(context.Background(), 42)
+	_, updateErr := uc.Update(context.Background(), 42, domain.UserPatch{Name: &name})
+	for _, err := range []error{getErr, updateErr} {
+		if status := apperrors.As(err).Kind.Status(); status != http.StatusForbidden {
+			t.Fatalf("status = %d (%v), want 403", status, err)
+		}
+	}
+	if touched {
+		t.Fatal("repository called without permission")
+	}
+	if len(checked) != 2 || checked[0] != "read users:42" || checked[1] != "update users:42" {
+		t.Fatalf("authorized %v, want read and update of users:42", checked)
+	}
+}
+
+func TestUserUsecase_UpdateLocksRow(t *testing.T) {
+	tm := &txRecorder{}
+	var saved *domain.User
+	repo := &interfaces.MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (*domain.User, error) {
+			t.Fatal("Update must read the user with GetByIDForUpdateTx")
+			return nil, nil
+		},
+		GetByIDForUpdateTxFunc: func(tx *sqlx.Tx, id int64) (*domain.User, error) {
+			if !tm.active {
+				t.Fatal("user locked outside of transaction")
+			}
+			return &domain.User{ID: id, Name: "Old", Email: "old@example.com"}, nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			if !tm.active {
+				t.Fatal("user saved outside of transaction")
+			}
+			saved = user
+			return nil
+		},
+	}
+	uc := newUserUsecase(t, repo, &interfaces.MockAuthorizer{}, tm)
+
+	name := "New"
+	user, err := uc.Update(context.Background(), 42, domain.UserPatch{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.executed != 1 {
+		t.Fatalf("transactions = %d, want 1", tm.executed)
+	}
+	if saved == nil || user.Name != "New" || user.Email != "old@example.com" {
+		t.Fatalf("saved %+v, returned %+v; want only name changed", saved, user)
+	}
+}
+
+func TestUserUsecase_EmailConflict(t *testing.T) {
+	repo := &interfaces.MockUserRepository{
+		CreateFunc: func(ctx context.Context, user *domain.User) error {
+			return domain.ErrUserEmailTaken
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			return domain.ErrUserEmailTaken
+		},
+	}
+	uc := newUserUsecase(t, repo, &interfaces.MockAuthorizer{}, &interfaces.MockTransactionManager{})
+
+	email := "taken@example.com"
+	_, createErr := uc.Create(context.Background(), "Иван", email)
+	_, updateErr := uc.Update(context.Background(), 42, domain.UserPatch{Email: &email})
+	for _, err := range []error{createErr, updateErr} {
+		if !errors.Is(err, domain.ErrUserEmailTaken) {
+			t.Fatalf("error = %v, want user_email_taken", err)
+		}
+		if status := apperrors.As(err).Kind.Status(); status != http.StatusConflict {
+			t.Fatalf("status = %d, want 409", status)
+		}
+	}
+}
@@ -0,0 +1 @@
+-- Базовая версия общей схемы, откатывать нечего (см. 000001_init.up.sql)
@@ -0,0 +1,5 @@
+-- Базовая версия общей схемы, намеренно пустая. Таблицы модулей (users, rbac,
+-- api_keys) создают миграции модулей в internal/modules/migrations/<module>
+-- со своей таблицей версий schema_migrations_<module>, поэтому применяются
+-- только для включённых модулей. Здесь - таблицы вне модулей (rate_limits).
+-- Файл не удаляется: базы, уже перешедшие на версию 1, ищут её при обновлении.
@@ -0,0 +1,10 @@
+-- Состояние лимитов internal/ratelimit для store: postgres
+CREATE TABLE IF NOT EXISTS rate_limits (
+    key        TEXT PRIMARY KEY,
+    value      DOUBLE PRECISION NOT NULL,
+    prev       DOUBLE PRECISION NOT NULL,
+    at         TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits (expires_at);